@@ -6,12 +6,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"go-messaging/config"
 	"go-messaging/database"
 	httpDelivery "go-messaging/delivery/http"
+	"go-messaging/internal/price"
 	"go-messaging/internal/scheduler"
 	"go-messaging/repository"
 	"go-messaging/service"
@@ -36,6 +39,12 @@ func main() {
 	// Initialize services
 	services := initializeServices(repos, cfg)
 
+	// Report notification types that cannot be dispatched
+	checkContentProviders(services)
+
+	// Create the scheduler before anything can change notification types
+	notificationScheduler := newNotificationScheduler(services)
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -47,10 +56,13 @@ func main() {
 	setupGracefulShutdown(cancel)
 
 	// Start notification scheduler
-	go startNotificationScheduler(ctx, services.NotificationDispatch)
+	go notificationScheduler.Start(ctx)
 
 	// Start cleanup scheduler
-	go startCleanupScheduler(ctx, services.Admin)
+	go startCleanupScheduler(ctx, services.Admin, repos.JobLock)
+
+	// Start outbox sender
+	go startOutboxScheduler(ctx, services.Outbox, cfg)
 
 	// Start Telegram bot
 	go func() {
@@ -97,19 +109,27 @@ func setupDatabase(cfg *config.Configurations) (*database.Database, error) {
 
 // Repositories holds all repository instances
 type Repositories struct {
-	User             repository.UserRepository
-	NotificationType repository.NotificationTypeRepository
-	Subscription     repository.SubscriptionRepository
-	NotificationLog  repository.NotificationLogRepository
+	User                 repository.UserRepository
+	NotificationType     repository.NotificationTypeRepository
+	Subscription         repository.SubscriptionRepository
+	NotificationLog      repository.NotificationLogRepository
+	PriceAlertState      repository.PriceAlertStateRepository
+	Outbox               repository.OutboxRepository
+	JobLock              repository.JobLockRepository
+	ApprovalNotification repository.ApprovalNotificationRepository
 }
 
 // initializeRepositories creates all repository instances
 func initializeRepositories(db *database.Database) *Repositories {
 	return &Repositories{
-		User:             repository.NewUserRepository(db.Connection),
-		NotificationType: repository.NewNotificationTypeRepository(db.Connection),
-		Subscription:     repository.NewSubscriptionRepository(db.Connection),
-		NotificationLog:  repository.NewNotificationLogRepository(db.Connection),
+		User:                 repository.NewUserRepository(db.Connection),
+		NotificationType:     repository.NewNotificationTypeRepository(db.Connection),
+		Subscription:         repository.NewSubscriptionRepository(db.Connection),
+		NotificationLog:      repository.NewNotificationLogRepository(db.Connection),
+		PriceAlertState:      repository.NewPriceAlertStateRepository(db.Connection),
+		Outbox:               repository.NewOutboxRepository(db.Connection),
+		JobLock:              repository.NewJobLockRepository(db.Connection),
+		ApprovalNotification: repository.NewApprovalNotificationRepository(db.Connection),
 	}
 }
 
@@ -122,22 +142,33 @@ type Services struct {
 	Admin                service.AdminServiceInterface
 	TelegramBot          *service.TelegramBotService
 	NotificationDispatch service.NotificationDispatchService
+	ContentProviders     *service.ContentProviderRegistry
+	Outbox               service.OutboxService
+	Channel              service.ChannelService
+	Broadcast            service.BroadcastService
+	DirectMessage        service.DirectMessageService
+	Detection            service.DetectionInterface
 }
 
 // initializeServices creates all service instances
 func initializeServices(repos *Repositories, cfg *config.Configurations) *Services {
 	userService := service.NewUserService(repos.User)
-	notificationTypeService := service.NewNotificationTypeService(repos.NotificationType)
+	notificationTypeService := service.NewNotificationTypeService(repos.NotificationType, repos.Subscription)
+	priceClient := newPriceClient(cfg)
 	subscriptionService := service.NewSubscriptionService(
 		repos.Subscription,
 		repos.User,
 		repos.NotificationType,
 		repos.NotificationLog,
+		priceClient,
 	)
 	notificationLogService := service.NewNotificationLogService(repos.NotificationLog)
 
 	// Create admin service
-	adminService := service.NewAdminService(repos.User)
+	adminService := service.NewAdminService(repos.User, repos.ApprovalNotification)
+
+	// Content providers generate the message body for each notification type
+	contentProviders := service.NewDefaultContentProviderRegistry(priceClient, repos.PriceAlertState)
 
 	// Create the main Telegram bot service
 	telegramBotService := service.NewTelegramBotService(
@@ -146,12 +177,30 @@ func initializeServices(repos *Repositories, cfg *config.Configurations) *Servic
 		subscriptionService,
 		notificationTypeService,
 		adminService,
+		contentProviders,
+		newOutboundRate(cfg),
+	)
+
+	// Group and channel admins are recognised through the bot
+	subscriptionService.SetChatAdminChecker(telegramBotService)
+
+	dispatchConfig := newDispatchConfig(cfg)
+
+	// Dispatch queues messages in the outbox; the bot's outbound limiter paces the outbox sender
+	outboxService := service.NewOutboxService(
+		repos.Outbox,
+		notificationLogService,
+		telegramBotService,
+		newOutboxConfig(cfg),
 	)
 
 	notificationDispatchService := service.NewNotificationDispatchService(
 		subscriptionService,
 		notificationLogService,
 		telegramBotService,
+		contentProviders,
+		outboxService,
+		dispatchConfig,
 	)
 
 	return &Services{
@@ -162,6 +211,102 @@ func initializeServices(repos *Repositories, cfg *config.Configurations) *Servic
 		Admin:                adminService,
 		TelegramBot:          telegramBotService,
 		NotificationDispatch: notificationDispatchService,
+		ContentProviders:     contentProviders,
+		Outbox:               outboxService,
+		Channel:              service.NewChannelService(telegramBotService, userService, subscriptionService),
+		Broadcast:            service.NewBroadcastService(notificationTypeService, subscriptionService, notificationDispatchService),
+		DirectMessage:        service.NewDirectMessageService(userService, outboxService),
+		Detection:            service.NewDetectionService(subscriptionService, notificationDispatchService),
+	}
+}
+
+// newPriceClient creates the price client used by price-based content providers
+func newPriceClient(cfg *config.Configurations) price.Client {
+	timeoutSeconds, err := strconv.Atoi(cfg.PRICE_API_TIMEOUT_SECONDS)
+	if err != nil || timeoutSeconds <= 0 {
+		log.Printf("⚠️ Invalid PRICE_API_TIMEOUT_SECONDS %q, using 10 seconds", cfg.PRICE_API_TIMEOUT_SECONDS)
+		timeoutSeconds = 10
+	}
+
+	return price.NewCachingClient(price.NewCoinbaseClient(price.CoinbaseConfig{
+		BaseURL: cfg.PRICE_API_BASE_URL,
+		Timeout: time.Duration(timeoutSeconds) * time.Second,
+	}))
+}
+
+// newDispatchConfig builds the dispatch worker pool settings from configuration
+func newDispatchConfig(cfg *config.Configurations) service.DispatchConfig {
+	dispatchConfig := service.DefaultDispatchConfig()
+
+	if n, err := strconv.Atoi(cfg.DISPATCH_CONCURRENCY); err == nil && n > 0 {
+		dispatchConfig.DefaultConcurrency = n
+	} else {
+		log.Printf("⚠️ Invalid DISPATCH_CONCURRENCY %q, using %d", cfg.DISPATCH_CONCURRENCY, dispatchConfig.DefaultConcurrency)
+	}
+
+	// Per-type overrides are given as "code=workers" pairs separated by commas
+	dispatchConfig.TypeConcurrency = make(map[string]int)
+	for _, pair := range strings.Split(cfg.DISPATCH_TYPE_CONCURRENCY, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(pair, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || n <= 0 {
+			log.Printf("⚠️ Ignoring invalid DISPATCH_TYPE_CONCURRENCY entry %q", pair)
+			continue
+		}
+		dispatchConfig.TypeConcurrency[strings.TrimSpace(code)] = n
+	}
+
+	return dispatchConfig
+}
+
+// defaultOutboundRate stays below Telegram's limit of 30 messages per second
+const defaultOutboundRate = 25
+
+// newOutboundRate reads the global cap on messages the bot sends per second
+func newOutboundRate(cfg *config.Configurations) int {
+	n, err := strconv.Atoi(cfg.DISPATCH_RATE_PER_SECOND)
+	if err != nil || n <= 0 {
+		log.Printf("⚠️ Invalid DISPATCH_RATE_PER_SECOND %q, using %d", cfg.DISPATCH_RATE_PER_SECOND, defaultOutboundRate)
+		return defaultOutboundRate
+	}
+	return n
+}
+
+// newOutboxConfig builds the outbox retry settings from configuration
+func newOutboxConfig(cfg *config.Configurations) service.OutboxConfig {
+	outboxConfig := service.DefaultOutboxConfig()
+
+	if n, err := strconv.Atoi(cfg.OUTBOX_MAX_ATTEMPTS); err == nil && n > 0 {
+		outboxConfig.MaxAttempts = n
+	} else {
+		log.Printf("⚠️ Invalid OUTBOX_MAX_ATTEMPTS %q, using %d", cfg.OUTBOX_MAX_ATTEMPTS, outboxConfig.MaxAttempts)
+	}
+
+	return outboxConfig
+}
+
+// checkContentProviders logs notification types that have no registered content provider
+func checkContentProviders(services *Services) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	types, err := services.NotificationType.GetAllTypes(ctx)
+	if err != nil {
+		log.Printf("⚠️ Failed to check content providers: %v", err)
+		return
+	}
+
+	missing := services.ContentProviders.Unregistered(types)
+	if len(missing) == 0 {
+		log.Printf("✅ Content providers registered for all %d notification types", len(types))
+		return
+	}
+
+	for _, code := range missing {
+		log.Printf("⚠️ Notification type '%s' has no content provider and will not be dispatched", code)
 	}
 }
 
@@ -176,14 +321,28 @@ func setupHTTPServer(services *Services, db *database.Database) *http.Server {
 	// Initialize handlers
 	userHandler := httpDelivery.NewUserHandler(services.User)
 	adminHandler := httpDelivery.NewAdminHandler(services.Admin)
+	outboxHandler := httpDelivery.NewOutboxHandler(services.Outbox)
+	notificationTypeHandler := httpDelivery.NewNotificationTypeHandler(services.NotificationType)
+	channelHandler := httpDelivery.NewChannelHandler(services.Channel)
+	subscriptionHandler := httpDelivery.NewSubscriptionHandler(services.Subscription)
+	broadcastHandler := httpDelivery.NewBroadcastHandler(services.Broadcast)
+	messageHandler := httpDelivery.NewMessageHandler(services.DirectMessage)
+	detectionHandler := httpDelivery.NewDetectionHandler(services.Detection)
 	authMiddleware := httpDelivery.NewBasicAuthMiddleware(db.Connection)
 
 	// Setup routes
 	routeConfig := &httpDelivery.RouteConfig{
-		Router:         router,
-		UserHandler:    userHandler,
-		AdminHandler:   adminHandler,
-		AuthMiddleware: authMiddleware,
+		Router:                  router,
+		UserHandler:             userHandler,
+		AdminHandler:            adminHandler,
+		OutboxHandler:           outboxHandler,
+		NotificationTypeHandler: notificationTypeHandler,
+		ChannelHandler:          channelHandler,
+		SubscriptionHandler:     subscriptionHandler,
+		BroadcastHandler:        broadcastHandler,
+		MessageHandler:          messageHandler,
+		DetectionHandler:        detectionHandler,
+		AuthMiddleware:          authMiddleware,
 	}
 	routeConfig.Setup()
 
@@ -217,15 +376,23 @@ func startHTTPServer(ctx context.Context, server *http.Server) {
 	}
 }
 
-// startNotificationScheduler starts the notification scheduling service
-func startNotificationScheduler(ctx context.Context, dispatchService service.NotificationDispatchService) {
-	notificationScheduler := scheduler.NewNotificationScheduler(dispatchService)
-	notificationScheduler.Start(ctx)
+// newNotificationScheduler creates the notification scheduling service
+func newNotificationScheduler(services *Services) *scheduler.NotificationScheduler {
+	notificationScheduler := scheduler.NewNotificationScheduler(
+		services.NotificationDispatch,
+		services.NotificationType,
+		services.Subscription,
+	)
+
+	// Type changes made by admins take effect without waiting for the next reload tick
+	services.NotificationType.SetChangeListener(notificationScheduler)
+
+	return notificationScheduler
 }
 
 // startCleanupScheduler starts the cleanup scheduling service
-func startCleanupScheduler(ctx context.Context, adminService service.AdminServiceInterface) {
-	cleanupScheduler := scheduler.NewCleanupScheduler(adminService)
+func startCleanupScheduler(ctx context.Context, adminService service.AdminServiceInterface, jobLocks repository.JobLockRepository) {
+	cleanupScheduler := scheduler.NewCleanupScheduler(adminService, jobLocks)
 	cleanupScheduler.Start()
 
 	// Stop scheduler when context is cancelled
@@ -235,6 +402,24 @@ func startCleanupScheduler(ctx context.Context, adminService service.AdminServic
 	}()
 }
 
+// startOutboxScheduler starts the loop that delivers queued messages
+func startOutboxScheduler(ctx context.Context, outboxService service.OutboxService, cfg *config.Configurations) {
+	pollSeconds, err := strconv.Atoi(cfg.OUTBOX_POLL_SECONDS)
+	if err != nil || pollSeconds <= 0 {
+		log.Printf("⚠️ Invalid OUTBOX_POLL_SECONDS %q, using 2 seconds", cfg.OUTBOX_POLL_SECONDS)
+		pollSeconds = 2
+	}
+
+	outboxScheduler := scheduler.NewOutboxScheduler(outboxService, time.Duration(pollSeconds)*time.Second, 50)
+	outboxScheduler.Start(ctx)
+
+	// Stop scheduler when context is cancelled
+	go func() {
+		<-ctx.Done()
+		outboxScheduler.Stop()
+	}()
+}
+
 // setupGracefulShutdown sets up signal handling for graceful shutdown
 func setupGracefulShutdown(cancel context.CancelFunc) {
 	signalChan := make(chan os.Signal, 1)
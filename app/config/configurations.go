@@ -19,6 +19,19 @@ type Configurations struct {
 	DB_PASSWORD string
 	DB_NAME     string
 	DB_SSLMODE  string
+
+	// Price API configuration
+	PRICE_API_BASE_URL        string
+	PRICE_API_TIMEOUT_SECONDS string
+
+	// Dispatch configuration
+	DISPATCH_CONCURRENCY      string
+	DISPATCH_TYPE_CONCURRENCY string // e.g. "news=8,coinbase=2"
+	DISPATCH_RATE_PER_SECOND  string
+
+	// Outbox configuration
+	OUTBOX_MAX_ATTEMPTS string
+	OUTBOX_POLL_SECONDS string
 }
 
 func LoadConfigurations() *Configurations {
@@ -43,6 +56,19 @@ func LoadConfigurations() *Configurations {
 		DB_PASSWORD: getEnvWithDefault("DB_PASSWORD", ""),
 		DB_NAME:     getEnvWithDefault("DB_NAME", "go_messaging"),
 		DB_SSLMODE:  getEnvWithDefault("DB_SSLMODE", "disable"),
+
+		// Price API configuration
+		PRICE_API_BASE_URL:        getEnvWithDefault("PRICE_API_BASE_URL", "https://api.coinbase.com"),
+		PRICE_API_TIMEOUT_SECONDS: getEnvWithDefault("PRICE_API_TIMEOUT_SECONDS", "10"),
+
+		// Dispatch configuration
+		DISPATCH_CONCURRENCY:      getEnvWithDefault("DISPATCH_CONCURRENCY", "4"),
+		DISPATCH_TYPE_CONCURRENCY: os.Getenv("DISPATCH_TYPE_CONCURRENCY"),
+		DISPATCH_RATE_PER_SECOND:  getEnvWithDefault("DISPATCH_RATE_PER_SECOND", "25"),
+
+		// Outbox configuration
+		OUTBOX_MAX_ATTEMPTS: getEnvWithDefault("OUTBOX_MAX_ATTEMPTS", "5"),
+		OUTBOX_POLL_SECONDS: getEnvWithDefault("OUTBOX_POLL_SECONDS", "2"),
 	}
 }
 
@@ -89,6 +89,9 @@ func (d *Database) AutoMigrate() error {
 		&entity.NotificationType{},
 		&entity.Subscription{},
 		&entity.NotificationLog{},
+		&entity.PriceAlertState{},
+		&entity.OutboundMessage{},
+		&entity.ApprovalNotification{},
 	)
 }
 
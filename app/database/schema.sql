@@ -17,6 +17,7 @@ CREATE TABLE IF NOT EXISTS users (
     approval_status VARCHAR(20) DEFAULT 'pending', -- 'pending', 'approved', 'rejected', 'disabled'
     approved_by UUID,
     approved_at TIMESTAMP WITH TIME ZONE,
+    timezone VARCHAR(64) DEFAULT 'UTC', -- IANA name used for schedules and quiet hours
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     FOREIGN KEY (approved_by) REFERENCES users(id)
@@ -38,6 +39,7 @@ CREATE TABLE IF NOT EXISTS notification_types (
     description TEXT,
     default_interval_minutes INTEGER DEFAULT 60,
     is_active BOOLEAN DEFAULT TRUE,
+    preference_schema JSONB DEFAULT '{}', -- allowed/required subscription preferences
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
@@ -61,20 +63,60 @@ CREATE TABLE IF NOT EXISTS subscriptions (
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     last_notified_at TIMESTAMP WITH TIME ZONE,
     
-    -- Ensure unique subscription per user per type
-    UNIQUE(user_id, notification_type_id)
+    -- A chat (private chat, group or channel) subscribes to a type at most once;
+    -- user_id is whoever subscribed it
+    UNIQUE(chat_id, notification_type_id)
 );
 
 -- Notification logs table (optional - for tracking sent notifications)
 CREATE TABLE IF NOT EXISTS notification_logs (
     id BIGSERIAL PRIMARY KEY,
-    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    subscription_id BIGINT REFERENCES subscriptions(id) ON DELETE CASCADE, -- NULL for direct messages
+    chat_id BIGINT, -- set for direct messages
     message TEXT NOT NULL,
-    status VARCHAR(20) DEFAULT 'sent', -- sent, failed, delivered
+    status VARCHAR(20) DEFAULT 'sent', -- sent, failed, delivered, skipped
     sent_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     error_message TEXT
 );
 
+-- Price alert state table (last observed price per price_alert subscription)
+CREATE TABLE IF NOT EXISTS price_alert_states (
+    subscription_id BIGINT PRIMARY KEY REFERENCES subscriptions(id) ON DELETE CASCADE,
+    last_price DOUBLE PRECISION,
+    state VARCHAR(10) NOT NULL, -- above, below
+    threshold DOUBLE PRECISION,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS outbound_messages (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT REFERENCES subscriptions(id) ON DELETE SET NULL,
+    chat_id BIGINT NOT NULL,
+    message TEXT NOT NULL,
+    parse_mode VARCHAR(20),
+    buttons JSONB,
+    status VARCHAR(20) DEFAULT 'pending', -- pending, sending, sent, dead
+    attempts INTEGER DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    locked_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    telegram_message_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Messages sent to admins about users waiting for approval, one per user and admin
+CREATE TABLE IF NOT EXISTS approval_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    admin_chat_id BIGINT NOT NULL,
+    message_id INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_notifications_user_chat ON approval_notifications(user_id, admin_chat_id);
+
 -- Indexes for performance
 CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions(user_id);
 CREATE INDEX IF NOT EXISTS idx_subscriptions_notification_type ON subscriptions(notification_type_id);
@@ -82,6 +124,8 @@ CREATE INDEX IF NOT EXISTS idx_subscriptions_active ON subscriptions(is_active);
 CREATE INDEX IF NOT EXISTS idx_subscriptions_chat_id ON subscriptions(chat_id);
 CREATE INDEX IF NOT EXISTS idx_notification_logs_subscription_id ON notification_logs(subscription_id);
 CREATE INDEX IF NOT EXISTS idx_notification_logs_sent_at ON notification_logs(sent_at);
+CREATE INDEX IF NOT EXISTS idx_notification_logs_chat_id ON notification_logs(chat_id);
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_due ON outbound_messages(status, next_attempt_at);
 
 -- Insert default notification types
 INSERT INTO notification_types (code, name, description, default_interval_minutes) VALUES
@@ -89,9 +133,53 @@ INSERT INTO notification_types (code, name, description, default_interval_minute
 ('news', 'News Alerts', 'Breaking news and important updates', 2),
 ('weather', 'Weather Updates', 'Weather forecasts and alerts', 4),
 ('price_alert', 'Price Alerts', 'Custom price threshold notifications', 5),
-('custom', 'Custom Notifications', 'Custom notifications for specific needs', 6)
+('custom', 'Custom Notifications', 'Custom notifications for specific needs', 6),
+('security', 'Security Alerts', 'Security-related notifications', 1)
 ON CONFLICT (code) DO NOTHING;
 
+-- Preference schemas for the default notification types
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "currency": {"type": "string", "format": "currency", "title": "💱 Currency", "description": "Which currency? Send a ticker such as BTC or ETH.", "default": "BTC"},
+    "quote": {"type": "string", "title": "💵 Quote currency", "description": "Which currency should prices be quoted in? Send a code such as USD or EUR. Send ''off'' for USD."}
+  }
+}' WHERE code = 'coinbase' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "currency": {"type": "string", "format": "currency", "title": "💱 Currency", "description": "Which currency? Send a ticker such as BTC or ETH.", "default": "BTC"},
+    "quote": {"type": "string", "title": "💵 Quote currency", "description": "Which currency should prices be quoted in? Send a code such as USD or EUR. Send ''off'' for USD."},
+    "threshold": {"type": "number", "format": "price", "title": "🎯 Threshold", "description": "At what price should I alert you? Send a number such as 3500.", "minimum": 0.01, "default": 50000},
+    "direction": {"type": "string", "title": "↕️ Direction", "description": "Alert when the price crosses the threshold going which way?", "enum": ["above", "below", "both"], "default": "above"},
+    "rearm_percent": {"type": "number", "title": "🔁 Re-arm margin", "description": "How far, in percent, must the price move back before the alert can fire again? Send ''off'' for the default.", "minimum": 0, "maximum": 50}
+  },
+  "required": ["currency", "threshold"]
+}' WHERE code = 'price_alert' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "keywords": {"type": "array", "title": "🔎 Keywords", "description": "Which topics? Send keywords separated by commas, e.g. golang,kubernetes. Send ''off'' to clear."}
+  }
+}' WHERE code = 'news' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "location": {"type": "string", "title": "📍 Location", "description": "Which location? Send a city name, e.g. Berlin."}
+  }
+}' WHERE code = 'weather' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "message": {"type": "string", "title": "💬 Message", "description": "What should the notification say?"}
+  }
+}' WHERE code = 'custom' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "min_risk": {"type": "string", "title": "🚨 Minimum risk", "description": "Which detections should I send you? Pick the lowest risk level.", "enum": ["low", "medium", "high", "critical"], "default": "high"}
+  }
+}' WHERE code = 'security' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
 -- Update triggers for updated_at timestamps
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-messaging/delivery/http/dto"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BroadcastHandler struct {
+	broadcastService service.BroadcastService
+}
+
+func NewBroadcastHandler(broadcastService service.BroadcastService) *BroadcastHandler {
+	return &BroadcastHandler{
+		broadcastService: broadcastService,
+	}
+}
+
+// POST /api/v1/admin/notification-types/:code/broadcast
+func (h *BroadcastHandler) Broadcast(c *gin.Context) {
+	var req dto.BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	job, err := h.broadcastService.Broadcast(c.Request.Context(), c.Param("code"), req.Message, req.DryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidBroadcastMessage):
+			status = http.StatusBadRequest
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   "Failed to start broadcast",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// A dry run is already finished; a real broadcast carries on in the background
+	status := http.StatusAccepted
+	if job.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, job)
+}
+
+// GET /api/v1/admin/broadcasts/:id
+func (h *BroadcastHandler) GetBroadcast(c *gin.Context) {
+	job, ok := h.broadcastService.GetJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Broadcast not found",
+			Message: "No broadcast with this ID was started on this instance",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
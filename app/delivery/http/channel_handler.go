@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"go-messaging/entity"
+	"go-messaging/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ChannelHandler struct {
+	channelService service.ChannelService
+}
+
+type ChannelSubscriptionRequest struct {
+	Channel          string                          `json:"channel" binding:"required"`
+	NotificationType string                          `json:"notification_type" binding:"required"`
+	AdminID          string                          `json:"admin_id" binding:"required"`
+	Preferences      *entity.SubscriptionPreferences `json:"preferences"`
+}
+
+func NewChannelHandler(channelService service.ChannelService) *ChannelHandler {
+	return &ChannelHandler{
+		channelService: channelService,
+	}
+}
+
+// POST /api/v1/admin/channels/subscriptions
+func (h *ChannelHandler) SubscribeChannel(c *gin.Context) {
+	var req ChannelSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	adminID, err := uuid.Parse(req.AdminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid admin ID format",
+		})
+		return
+	}
+
+	subscription, channel, err := h.channelService.SubscribeChannel(c.Request.Context(), adminID, req.Channel, req.NotificationType, req.Preferences)
+	if err != nil {
+		c.JSON(channelErrorStatus(err), gin.H{
+			"error":   "Failed to subscribe channel",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Channel subscribed successfully",
+		"channel":      channel,
+		"subscription": subscription,
+	})
+}
+
+// GET /api/v1/admin/channels/:channel/subscriptions
+func (h *ChannelHandler) GetChannelSubscriptions(c *gin.Context) {
+	subscriptions, channel, err := h.channelService.GetChannelSubscriptions(c.Request.Context(), c.Param("channel"))
+	if err != nil {
+		c.JSON(channelErrorStatus(err), gin.H{
+			"error":   "Failed to get channel subscriptions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"channel":       channel,
+		"subscriptions": subscriptions,
+		"count":         len(subscriptions),
+	})
+}
+
+// DELETE /api/v1/admin/channels/:channel/subscriptions/:code
+func (h *ChannelHandler) UnsubscribeChannel(c *gin.Context) {
+	adminIDParam := c.Query("admin_id")
+	if adminIDParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "admin_id query parameter is required",
+		})
+		return
+	}
+
+	adminID, err := uuid.Parse(adminIDParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid admin ID format",
+		})
+		return
+	}
+
+	if err := h.channelService.UnsubscribeChannel(c.Request.Context(), adminID, c.Param("channel"), c.Param("code")); err != nil {
+		c.JSON(channelErrorStatus(err), gin.H{
+			"error":   "Failed to unsubscribe channel",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Channel unsubscribed successfully",
+	})
+}
+
+// channelErrorStatus maps channel service errors to HTTP status codes
+func channelErrorStatus(err error) int {
+	var preferenceErr *service.PreferenceError
+	switch {
+	case errors.Is(err, service.ErrChannelNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrChannelCannotPost):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrNotAdmin):
+		return http.StatusForbidden
+	case errors.As(err, &preferenceErr):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -1,27 +1,51 @@
 package http
 
 import (
+	"errors"
+	"net/http"
+
+	"go-messaging/delivery/http/dto"
 	"go-messaging/model"
+	"go-messaging/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type DetectionHandler struct {
-	// Add necessary fields here
+	detectionService service.DetectionInterface
 }
 
 // NewDetectionHandler creates a new instance of DetectionHandler
-func NewDetectionHandler() *DetectionHandler {
-	return &DetectionHandler{}
+func NewDetectionHandler(detectionService service.DetectionInterface) *DetectionHandler {
+	return &DetectionHandler{
+		detectionService: detectionService,
+	}
 }
 
-// Add methods for DetectionHandler here
+// POST /api/v1/detections
 func (h *DetectionHandler) SendDetectionNotification(c *gin.Context) {
 	var req model.DetectionSummary
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.detectionService.SendDetectionNotification(c.Request.Context(), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidDetection) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   "Failed to send detection notification",
+			Message: err.Error(),
+		})
 		return
 	}
 
-	c.JSON(200, gin.H{"status": "Detection notification received", "data": req})
+	// Messages are queued in the outbox; skipped subscriptions are below their min_risk
+	c.JSON(http.StatusAccepted, result)
 }
@@ -0,0 +1,7 @@
+package dto
+
+// BroadcastRequest represents the request body for broadcasting to a notification type
+type BroadcastRequest struct {
+	Message string `json:"message" binding:"required"`
+	DryRun  bool   `json:"dry_run"`
+}
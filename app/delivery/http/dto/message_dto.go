@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"go-messaging/entity"
+
+	"github.com/google/uuid"
+)
+
+// SendMessageRequest represents the request body for sending a direct message. Exactly
+// one of TelegramUserID, UserID and ChatID is required.
+type SendMessageRequest struct {
+	TelegramUserID *int64               `json:"telegram_user_id,omitempty"`
+	UserID         *uuid.UUID           `json:"user_id,omitempty"`
+	ChatID         *int64               `json:"chat_id,omitempty"`
+	Text           string               `json:"text" binding:"required"`
+	ParseMode      string               `json:"parse_mode,omitempty"` // MarkdownV2, HTML or Markdown; plain text by default
+	Buttons        entity.InlineButtons `json:"buttons,omitempty"`
+}
+
+// MessageResponse represents a queued direct message and its delivery status
+type MessageResponse struct {
+	ID                int64      `json:"id"`
+	ChatID            int64      `json:"chat_id"`
+	Status            string     `json:"status"`
+	Attempts          int        `json:"attempts"`
+	LastError         *string    `json:"last_error,omitempty"`
+	TelegramMessageID *int       `json:"telegram_message_id,omitempty"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
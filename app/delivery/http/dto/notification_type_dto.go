@@ -0,0 +1,39 @@
+package dto
+
+import "go-messaging/entity"
+
+// NotificationTypeResponse represents a notification type and the preferences it accepts
+type NotificationTypeResponse struct {
+	ID                     int                     `json:"id"`
+	Code                   string                  `json:"code"`
+	Name                   string                  `json:"name"`
+	Description            *string                 `json:"description,omitempty"`
+	DefaultIntervalMinutes int                     `json:"default_interval_minutes"`
+	IsActive               bool                    `json:"is_active"`
+	PreferenceSchema       entity.PreferenceSchema `json:"preference_schema"`
+}
+
+// NotificationTypeStatsResponse represents a notification type with how many active
+// subscriptions it has
+type NotificationTypeStatsResponse struct {
+	NotificationTypeResponse
+	ActiveSubscriptions int64 `json:"active_subscriptions"`
+}
+
+// CreateNotificationTypeRequest represents the request body for creating a notification type
+type CreateNotificationTypeRequest struct {
+	Code                   string                   `json:"code" binding:"required"`
+	Name                   string                   `json:"name" binding:"required"`
+	Description            *string                  `json:"description,omitempty"`
+	DefaultIntervalMinutes int                      `json:"default_interval_minutes" binding:"required,min=1"`
+	PreferenceSchema       *entity.PreferenceSchema `json:"preference_schema,omitempty"`
+}
+
+// UpdateNotificationTypeRequest represents the request body for updating a notification
+// type; fields left out are unchanged
+type UpdateNotificationTypeRequest struct {
+	Name                   *string                  `json:"name,omitempty"`
+	Description            *string                  `json:"description,omitempty"`
+	DefaultIntervalMinutes *int                     `json:"default_interval_minutes,omitempty" binding:"omitempty,min=1"`
+	PreferenceSchema       *entity.PreferenceSchema `json:"preference_schema,omitempty"`
+}
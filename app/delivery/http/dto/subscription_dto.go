@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"go-messaging/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreateSubscriptionRequest represents the request body for subscribing a chat
+type CreateSubscriptionRequest struct {
+	TelegramUserID   int64                           `json:"telegram_user_id" binding:"required"`
+	ChatID           *int64                          `json:"chat_id,omitempty"` // defaults to the user's private chat
+	NotificationType string                          `json:"notification_type" binding:"required"`
+	Preferences      *entity.SubscriptionPreferences `json:"preferences,omitempty"`
+}
+
+// UpdateSubscriptionPreferencesRequest represents the request body for replacing a
+// subscription's preferences
+type UpdateSubscriptionPreferencesRequest struct {
+	Preferences *entity.SubscriptionPreferences `json:"preferences" binding:"required"`
+}
+
+// ListSubscriptionsQuery represents the filters and paging of a subscription listing
+type ListSubscriptionsQuery struct {
+	PaginationQuery
+	UserID         string `form:"user_id"`
+	TelegramUserID *int64 `form:"telegram_user_id"`
+	ChatID         *int64 `form:"chat_id"`
+	Type           string `form:"type"`
+	Active         *bool  `form:"active"`
+}
+
+// SubscriptionResponse represents a subscription
+type SubscriptionResponse struct {
+	ID                 int64                          `json:"id"`
+	UserID             uuid.UUID                      `json:"user_id"`
+	ChatID             int64                          `json:"chat_id"`
+	NotificationTypeID int                            `json:"notification_type_id"`
+	NotificationType   string                         `json:"notification_type"`
+	IsActive           bool                           `json:"is_active"`
+	Preferences        entity.SubscriptionPreferences `json:"preferences"`
+	LastNotifiedAt     *time.Time                     `json:"last_notified_at,omitempty"`
+	CreatedAt          time.Time                      `json:"created_at"`
+	UpdatedAt          time.Time                      `json:"updated_at"`
+}
+
+// PaginatedSubscriptionsResponse represents a page of subscriptions
+type PaginatedSubscriptionsResponse struct {
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+	Page          int                    `json:"page"`
+	Limit         int                    `json:"limit"`
+	Total         int64                  `json:"total"`
+}
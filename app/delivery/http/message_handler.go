@@ -0,0 +1,106 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MessageHandler struct {
+	directMessageService service.DirectMessageService
+}
+
+func NewMessageHandler(directMessageService service.DirectMessageService) *MessageHandler {
+	return &MessageHandler{
+		directMessageService: directMessageService,
+	}
+}
+
+// POST /api/v1/messages
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	var req dto.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message, err := h.directMessageService.Send(c.Request.Context(), service.DirectMessage{
+		TelegramUserID: req.TelegramUserID,
+		UserID:         req.UserID,
+		ChatID:         req.ChatID,
+		Text:           req.Text,
+		ParseMode:      req.ParseMode,
+		Buttons:        req.Buttons,
+	})
+	if err != nil {
+		var notApproved *service.UserNotApprovedError
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidDirectMessage):
+			status = http.StatusBadRequest
+		case errors.As(err, &notApproved):
+			status = http.StatusForbidden
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   "Failed to send message",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// The outbox delivers the message; poll GET /api/v1/messages/:id for the result
+	c.JSON(http.StatusAccepted, h.entityToResponse(message))
+}
+
+// GET /api/v1/messages/:id
+func (h *MessageHandler) GetMessage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid message ID",
+			Message: "Message ID must be a valid integer",
+		})
+		return
+	}
+
+	message, err := h.directMessageService.GetMessage(c.Request.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasSuffix(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   "Failed to get message",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.entityToResponse(message))
+}
+
+func (h *MessageHandler) entityToResponse(message *entity.OutboundMessage) dto.MessageResponse {
+	return dto.MessageResponse{
+		ID:                message.ID,
+		ChatID:            message.ChatID,
+		Status:            message.Status,
+		Attempts:          message.Attempts,
+		LastError:         message.LastError,
+		TelegramMessageID: message.TelegramMessageID,
+		SentAt:            message.SentAt,
+		CreatedAt:         message.CreatedAt,
+	}
+}
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"errors"
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationTypeHandler struct {
+	notificationTypeService service.NotificationTypeService
+}
+
+func NewNotificationTypeHandler(notificationTypeService service.NotificationTypeService) *NotificationTypeHandler {
+	return &NotificationTypeHandler{
+		notificationTypeService: notificationTypeService,
+	}
+}
+
+// GET /api/v1/notification-types
+func (h *NotificationTypeHandler) ListTypes(c *gin.Context) {
+	types, err := h.notificationTypeService.GetActiveTypes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list notification types",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	responses := make([]dto.NotificationTypeResponse, len(types))
+	for i, notificationType := range types {
+		responses[i] = h.entityToResponse(notificationType)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notification_types": responses,
+		"count":              len(responses),
+	})
+}
+
+// GET /api/v1/notification-types/:code
+func (h *NotificationTypeHandler) GetType(c *gin.Context) {
+	code := c.Param("code")
+
+	notificationType, err := h.notificationTypeService.GetTypeByCode(c.Request.Context(), code)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Notification type not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to get notification type",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.entityToResponse(notificationType))
+}
+
+// GET /api/v1/admin/notification-types
+func (h *NotificationTypeHandler) ListAllTypes(c *gin.Context) {
+	types, err := h.notificationTypeService.GetAllTypes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list notification types",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	counts, err := h.notificationTypeService.GetSubscriberCounts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to count subscriptions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	responses := make([]dto.NotificationTypeStatsResponse, len(types))
+	for i, notificationType := range types {
+		responses[i] = dto.NotificationTypeStatsResponse{
+			NotificationTypeResponse: h.entityToResponse(notificationType),
+			ActiveSubscriptions:      counts[notificationType.ID],
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notification_types": responses,
+		"count":              len(responses),
+	})
+}
+
+// POST /api/v1/admin/notification-types
+func (h *NotificationTypeHandler) CreateType(c *gin.Context) {
+	var req dto.CreateNotificationTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var schema entity.PreferenceSchema
+	if req.PreferenceSchema != nil {
+		schema = *req.PreferenceSchema
+	}
+
+	notificationType, err := h.notificationTypeService.CreateType(c.Request.Context(), req.Code, req.Name, req.Description, req.DefaultIntervalMinutes, schema)
+	if err != nil {
+		h.respondError(c, "Failed to create notification type", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.entityToResponse(notificationType))
+}
+
+// PUT /api/v1/admin/notification-types/:code
+func (h *NotificationTypeHandler) UpdateType(c *gin.Context) {
+	var req dto.UpdateNotificationTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	notificationType, err := h.notificationTypeService.GetTypeByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.respondError(c, "Failed to get notification type", err)
+		return
+	}
+
+	if req.Name != nil {
+		notificationType.Name = *req.Name
+	}
+	if req.Description != nil {
+		notificationType.Description = req.Description
+	}
+	if req.DefaultIntervalMinutes != nil {
+		notificationType.DefaultIntervalMinutes = *req.DefaultIntervalMinutes
+	}
+	if req.PreferenceSchema != nil {
+		notificationType.PreferenceSchema = *req.PreferenceSchema
+	}
+
+	if err := h.notificationTypeService.UpdateType(c.Request.Context(), notificationType); err != nil {
+		h.respondError(c, "Failed to update notification type", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.entityToResponse(notificationType))
+}
+
+// POST /api/v1/admin/notification-types/:code/activate
+func (h *NotificationTypeHandler) ActivateType(c *gin.Context) {
+	if err := h.notificationTypeService.ActivateType(c.Request.Context(), c.Param("code")); err != nil {
+		h.respondError(c, "Failed to activate notification type", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Notification type activated"})
+}
+
+// POST /api/v1/admin/notification-types/:code/deactivate
+func (h *NotificationTypeHandler) DeactivateType(c *gin.Context) {
+	if err := h.notificationTypeService.DeactivateType(c.Request.Context(), c.Param("code")); err != nil {
+		h.respondError(c, "Failed to deactivate notification type", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Notification type deactivated"})
+}
+
+// DELETE /api/v1/admin/notification-types/:code[?force=true]
+func (h *NotificationTypeHandler) DeleteType(c *gin.Context) {
+	force := c.Query("force") == "true"
+
+	if err := h.notificationTypeService.DeleteType(c.Request.Context(), c.Param("code"), force); err != nil {
+		h.respondError(c, "Failed to delete notification type", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError answers with the status code matching a notification type service error
+func (h *NotificationTypeHandler) respondError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrInvalidTypeCode), errors.Is(err, service.ErrInvalidTypeInterval),
+		errors.Is(err, service.ErrInvalidPreferenceSchema):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrTypeHasSubscribers), strings.HasSuffix(err.Error(), "already exists"):
+		status = http.StatusConflict
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func (h *NotificationTypeHandler) entityToResponse(notificationType *entity.NotificationType) dto.NotificationTypeResponse {
+	return dto.NotificationTypeResponse{
+		ID:                     notificationType.ID,
+		Code:                   notificationType.Code,
+		Name:                   notificationType.Name,
+		Description:            notificationType.Description,
+		DefaultIntervalMinutes: notificationType.DefaultIntervalMinutes,
+		IsActive:               notificationType.IsActive,
+		PreferenceSchema:       notificationType.PreferenceSchema,
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"go-messaging/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxHandler struct {
+	outboxService service.OutboxService
+}
+
+func NewOutboxHandler(outboxService service.OutboxService) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService: outboxService,
+	}
+}
+
+// GET /api/v1/admin/outbox/dead
+func (h *OutboxHandler) GetDeadLetters(c *gin.Context) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid offset parameter",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit parameter",
+		})
+		return
+	}
+
+	messages, total, err := h.outboxService.GetDeadLetters(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get dead letters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+		"count":    len(messages),
+		"total":    total,
+	})
+}
+
+// POST /api/v1/admin/outbox/:id/requeue
+func (h *OutboxHandler) Requeue(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid message ID format",
+		})
+		return
+	}
+
+	if err := h.outboxService.Requeue(c.Request.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "outbound message not found":
+			status = http.StatusNotFound
+		case "outbound message is not dead-lettered":
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to requeue message",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Message requeued successfully",
+		"id":      id,
+	})
+}
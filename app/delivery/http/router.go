@@ -3,10 +3,17 @@ package http
 import "github.com/gin-gonic/gin"
 
 type RouteConfig struct {
-	Router         *gin.Engine
-	UserHandler    *UserHandler
-	AdminHandler   *AdminHandler
-	AuthMiddleware *BasicAuthMiddleware
+	Router                  *gin.Engine
+	UserHandler             *UserHandler
+	AdminHandler            *AdminHandler
+	OutboxHandler           *OutboxHandler
+	NotificationTypeHandler *NotificationTypeHandler
+	ChannelHandler          *ChannelHandler
+	SubscriptionHandler     *SubscriptionHandler
+	BroadcastHandler        *BroadcastHandler
+	MessageHandler          *MessageHandler
+	DetectionHandler        *DetectionHandler
+	AuthMiddleware          *BasicAuthMiddleware
 }
 
 func (c *RouteConfig) Setup() {
@@ -28,6 +35,43 @@ func (c *RouteConfig) Setup() {
 			users.DELETE("/telegram/:telegram_user_id", c.UserHandler.DeleteUser)
 		}
 
+		// Notification type routes
+		if c.NotificationTypeHandler != nil {
+			types := v1.Group("/notification-types")
+			{
+				types.GET("", c.NotificationTypeHandler.ListTypes)
+				types.GET("/:code", c.NotificationTypeHandler.GetType)
+			}
+		}
+
+		// Subscription routes for other services, with API credentials
+		if c.SubscriptionHandler != nil {
+			subscriptions := v1.Group("/subscriptions", c.apiAuth())
+			{
+				subscriptions.GET("", c.SubscriptionHandler.ListSubscriptions)
+				subscriptions.POST("", c.SubscriptionHandler.CreateSubscription)
+				subscriptions.GET("/:id", c.SubscriptionHandler.GetSubscription)
+				subscriptions.PUT("/:id/preferences", c.SubscriptionHandler.UpdatePreferences)
+				subscriptions.POST("/:id/pause", c.SubscriptionHandler.PauseSubscription)
+				subscriptions.POST("/:id/resume", c.SubscriptionHandler.ResumeSubscription)
+				subscriptions.DELETE("/:id", c.SubscriptionHandler.DeleteSubscription)
+			}
+		}
+
+		// Direct message routes for other services, with API credentials
+		if c.MessageHandler != nil {
+			messages := v1.Group("/messages", c.apiAuth())
+			{
+				messages.POST("", c.MessageHandler.SendMessage)
+				messages.GET("/:id", c.MessageHandler.GetMessage)
+			}
+		}
+
+		// Detection results from the scanner, with API credentials
+		if c.DetectionHandler != nil {
+			v1.POST("/detections", c.apiAuth(), c.DetectionHandler.SendDetectionNotification)
+		}
+
 		// Admin routes with authentication
 		if c.AdminHandler != nil {
 			admin := v1.Group("/admin")
@@ -51,6 +95,40 @@ func (c *RouteConfig) Setup() {
 				admin.GET("/stats", c.AdminHandler.GetUserStats)
 				admin.POST("/cleanup", c.AdminHandler.CleanupPendingUsers)
 			}
+
+			if c.OutboxHandler != nil {
+				admin.GET("/outbox/dead", c.OutboxHandler.GetDeadLetters)
+				admin.POST("/outbox/:id/requeue", c.OutboxHandler.Requeue)
+			}
+
+			if c.NotificationTypeHandler != nil {
+				admin.GET("/notification-types", c.NotificationTypeHandler.ListAllTypes)
+				admin.POST("/notification-types", c.NotificationTypeHandler.CreateType)
+				admin.PUT("/notification-types/:code", c.NotificationTypeHandler.UpdateType)
+				admin.POST("/notification-types/:code/activate", c.NotificationTypeHandler.ActivateType)
+				admin.POST("/notification-types/:code/deactivate", c.NotificationTypeHandler.DeactivateType)
+				admin.DELETE("/notification-types/:code", c.NotificationTypeHandler.DeleteType)
+			}
+
+			if c.BroadcastHandler != nil {
+				admin.POST("/notification-types/:code/broadcast", c.BroadcastHandler.Broadcast)
+				admin.GET("/broadcasts/:id", c.BroadcastHandler.GetBroadcast)
+			}
+
+			if c.ChannelHandler != nil {
+				admin.POST("/channels/subscriptions", c.ChannelHandler.SubscribeChannel)
+				admin.GET("/channels/:channel/subscriptions", c.ChannelHandler.GetChannelSubscriptions)
+				admin.DELETE("/channels/:channel/subscriptions/:code", c.ChannelHandler.UnsubscribeChannel)
+			}
 		}
 	}
 }
+
+// apiAuth checks the API credentials of routes called by other services
+func (c *RouteConfig) apiAuth() gin.HandlerFunc {
+	if c.AuthMiddleware != nil {
+		return c.AuthMiddleware.BasicAuth()
+	}
+	// Fallback to simple basic auth for development
+	return SimpleBasicAuth("admin", "admin123")
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SubscriptionHandler struct {
+	subscriptionService service.SubscriptionService
+}
+
+func NewSubscriptionHandler(subscriptionService service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// GET /api/v1/subscriptions
+func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	var query dto.ListSubscriptionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	filter := service.SubscriptionFilter{
+		TelegramUserID:       query.TelegramUserID,
+		ChatID:               query.ChatID,
+		NotificationTypeCode: query.Type,
+		IsActive:             query.Active,
+	}
+	if query.UserID != "" {
+		userID, err := uuid.Parse(query.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid user ID format",
+				Message: "user_id must be a valid UUID",
+			})
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	subscriptions, total, err := h.subscriptionService.ListSubscriptions(c.Request.Context(), filter, offset, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list subscriptions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	responses := make([]dto.SubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		responses[i] = h.entityToResponse(subscription)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedSubscriptionsResponse{
+		Subscriptions: responses,
+		Page:          query.Page,
+		Limit:         query.Limit,
+		Total:         total,
+	})
+}
+
+// GET /api/v1/subscriptions/:id
+func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.subscriptionService.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, "Failed to get subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.entityToResponse(subscription))
+}
+
+// POST /api/v1/subscriptions
+func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
+	var req dto.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Without a chat the user is subscribed in their private chat with the bot
+	chatID := req.TelegramUserID
+	if req.ChatID != nil {
+		chatID = *req.ChatID
+	}
+
+	subscription, err := h.subscriptionService.Subscribe(c.Request.Context(), req.TelegramUserID, chatID, req.NotificationType, req.Preferences)
+	if err != nil {
+		h.respondError(c, "Failed to create subscription", err)
+		return
+	}
+
+	// Reload to return the subscription with its notification type
+	created, err := h.subscriptionService.GetSubscription(c.Request.Context(), subscription.ID)
+	if err != nil {
+		h.respondError(c, "Failed to get subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.entityToResponse(created))
+}
+
+// PUT /api/v1/subscriptions/:id/preferences
+func (h *SubscriptionHandler) UpdatePreferences(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSubscriptionPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.subscriptionService.UpdateSubscriptionPreferences(c.Request.Context(), id, req.Preferences)
+	if err != nil {
+		h.respondError(c, "Failed to update preferences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.entityToResponse(subscription))
+}
+
+// POST /api/v1/subscriptions/:id/pause
+func (h *SubscriptionHandler) PauseSubscription(c *gin.Context) {
+	h.setActive(c, false)
+}
+
+// POST /api/v1/subscriptions/:id/resume
+func (h *SubscriptionHandler) ResumeSubscription(c *gin.Context) {
+	h.setActive(c, true)
+}
+
+func (h *SubscriptionHandler) setActive(c *gin.Context, active bool) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.subscriptionService.SetSubscriptionActive(c.Request.Context(), id, active)
+	if err != nil {
+		h.respondError(c, "Failed to update subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.entityToResponse(subscription))
+}
+
+// DELETE /api/v1/subscriptions/:id
+func (h *SubscriptionHandler) DeleteSubscription(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionService.DeleteSubscription(c.Request.Context(), id); err != nil {
+		h.respondError(c, "Failed to delete subscription", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseID reads the subscription ID path parameter, answering 400 if it is not a number
+func (h *SubscriptionHandler) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid subscription ID",
+			Message: "Subscription ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError answers with the status code matching a subscription service error
+func (h *SubscriptionHandler) respondError(c *gin.Context, message string, err error) {
+	var notApproved *service.UserNotApprovedError
+	var preferenceErr *service.PreferenceError
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrSubscriptionNotFound):
+		status = http.StatusNotFound
+	case errors.As(err, &notApproved), errors.Is(err, service.ErrChatAccessDenied):
+		status = http.StatusForbidden
+	case errors.As(err, &preferenceErr):
+		status = http.StatusBadRequest
+	case strings.Contains(err.Error(), "not found"):
+		// Unknown users and notification types
+		status = http.StatusNotFound
+	case strings.HasSuffix(err.Error(), "is not active"):
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func (h *SubscriptionHandler) entityToResponse(subscription *entity.Subscription) dto.SubscriptionResponse {
+	return dto.SubscriptionResponse{
+		ID:                 subscription.ID,
+		UserID:             subscription.UserID,
+		ChatID:             subscription.ChatID,
+		NotificationTypeID: subscription.NotificationTypeID,
+		NotificationType:   subscription.NotificationType.Code,
+		IsActive:           subscription.IsActive,
+		Preferences:        subscription.Preferences,
+		LastNotifiedAt:     subscription.LastNotifiedAt,
+		CreatedAt:          subscription.CreatedAt,
+		UpdatedAt:          subscription.UpdatedAt,
+	}
+}
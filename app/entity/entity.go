@@ -19,6 +19,7 @@ type User struct {
 	ApprovalStatus string     `json:"approval_status" gorm:"default:'pending';index"` // 'pending', 'approved', 'rejected', 'disabled'
 	ApprovedBy     *uuid.UUID `json:"approved_by,omitempty" gorm:"type:uuid;index"`
 	ApprovedAt     *time.Time `json:"approved_at,omitempty"`
+	Timezone       string     `json:"timezone" gorm:"default:'UTC'"` // IANA name, e.g. 'Europe/Berlin'
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 
@@ -28,25 +29,49 @@ type User struct {
 }
 
 type NotificationType struct {
-	ID                     int       `json:"id" gorm:"primaryKey"`
-	Code                   string    `json:"code" gorm:"uniqueIndex;not null"`
-	Name                   string    `json:"name" gorm:"not null"`
-	Description            *string   `json:"description"`
-	DefaultIntervalMinutes int       `json:"default_interval_minutes" gorm:"default:60"`
-	IsActive               bool      `json:"is_active" gorm:"default:true"`
-	CreatedAt              time.Time `json:"created_at"`
-	UpdatedAt              time.Time `json:"updated_at"`
+	ID                     int              `json:"id" gorm:"primaryKey"`
+	Code                   string           `json:"code" gorm:"uniqueIndex;not null"`
+	Name                   string           `json:"name" gorm:"not null"`
+	Description            *string          `json:"description"`
+	DefaultIntervalMinutes int              `json:"default_interval_minutes" gorm:"default:60"`
+	IsActive               bool             `json:"is_active" gorm:"default:true"`
+	PreferenceSchema       PreferenceSchema `json:"preference_schema" gorm:"type:jsonb"`
+	CreatedAt              time.Time        `json:"created_at"`
+	UpdatedAt              time.Time        `json:"updated_at"`
 
 	// Relationships
 	Subscriptions []Subscription `json:"subscriptions,omitempty" gorm:"foreignKey:NotificationTypeID"`
 }
 
+// PreferenceSchema is a JSON-schema-like description of the preferences a notification type accepts.
+// Keys that are not fields of SubscriptionPreferences are stored in its Settings map.
+type PreferenceSchema struct {
+	Properties map[string]PreferenceProperty `json:"properties,omitempty"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+// PreferenceProperty describes a single preference
+type PreferenceProperty struct {
+	Type        string      `json:"type"`                  // 'string', 'number', 'integer', 'array'
+	Format      string      `json:"format,omitempty"`      // 'currency', 'price', 'cron', 'time_window'
+	Title       string      `json:"title,omitempty"`       // label shown in the bot
+	Description string      `json:"description,omitempty"` // prompt shown when the bot asks for a value
+	Default     interface{} `json:"default,omitempty"`
+	Minimum     *float64    `json:"minimum,omitempty"`
+	Maximum     *float64    `json:"maximum,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
+}
+
 type SubscriptionPreferences struct {
-	Currency  string            `json:"currency,omitempty"`
-	Interval  int               `json:"interval,omitempty"` // minutes
-	Keywords  []string          `json:"keywords,omitempty"`
-	Threshold float64           `json:"threshold,omitempty"`
-	Settings  map[string]string `json:"settings,omitempty"`
+	Currency     string            `json:"currency,omitempty"`
+	Interval     int               `json:"interval,omitempty"`    // minutes
+	Schedule     string            `json:"schedule,omitempty"`    // cron expression in the user's time zone, e.g. '0 7 * * 1-5'
+	QuietHours   string            `json:"quiet_hours,omitempty"` // no sends in this window, e.g. '23:00-07:00'
+	Keywords     []string          `json:"keywords,omitempty"`
+	Threshold    float64           `json:"threshold,omitempty"`
+	Direction    string            `json:"direction,omitempty"`     // 'above', 'below', 'both'
+	RearmPercent float64           `json:"rearm_percent,omitempty"` // hysteresis band as % of threshold
+	Settings     map[string]string `json:"settings,omitempty"`
 }
 
 type Subscription struct {
@@ -68,9 +93,10 @@ type Subscription struct {
 
 type NotificationLog struct {
 	ID             int64     `json:"id" gorm:"primaryKey"`
-	SubscriptionID int64     `json:"subscription_id" gorm:"not null;index"`
+	SubscriptionID *int64    `json:"subscription_id,omitempty" gorm:"index"` // nil for direct messages
+	ChatID         *int64    `json:"chat_id,omitempty" gorm:"index"`         // set for direct messages
 	Message        string    `json:"message" gorm:"not null"`
-	Status         string    `json:"status" gorm:"default:'sent'"` // sent, failed, delivered
+	Status         string    `json:"status" gorm:"default:'sent'"` // sent, failed, delivered, skipped
 	SentAt         time.Time `json:"sent_at"`
 	ErrorMessage   *string   `json:"error_message"`
 
@@ -78,6 +104,63 @@ type NotificationLog struct {
 	Subscription Subscription `json:"subscription,omitempty" gorm:"foreignKey:SubscriptionID"`
 }
 
+// PriceAlertState stores the last observed price for edge-triggered price alerts
+type PriceAlertState struct {
+	SubscriptionID int64     `json:"subscription_id" gorm:"primaryKey;autoIncrement:false"`
+	LastPrice      float64   `json:"last_price"`
+	State          string    `json:"state" gorm:"not null"` // 'above', 'below' relative to the threshold
+	Threshold      float64   `json:"threshold"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Outbound message statuses
+const (
+	OutboundStatusPending = "pending"
+	OutboundStatusSending = "sending"
+	OutboundStatusSent    = "sent"
+	OutboundStatusDead    = "dead"
+)
+
+// OutboundMessage is a queued Telegram message waiting to be delivered
+type OutboundMessage struct {
+	ID                int64         `json:"id" gorm:"primaryKey"`
+	SubscriptionID    *int64        `json:"subscription_id,omitempty" gorm:"index"`
+	ChatID            int64         `json:"chat_id" gorm:"not null"`
+	Message           string        `json:"message" gorm:"not null"`
+	ParseMode         string        `json:"parse_mode,omitempty" gorm:"size:20"` // empty for plain text, MarkdownV2 or HTML
+	Buttons           InlineButtons `json:"buttons,omitempty" gorm:"type:jsonb"`
+	Status            string        `json:"status" gorm:"default:'pending';index"` // pending, sending, sent, dead
+	Attempts          int           `json:"attempts" gorm:"default:0"`
+	NextAttemptAt     time.Time     `json:"next_attempt_at" gorm:"index"`
+	LockedAt          *time.Time    `json:"locked_at,omitempty"`
+	LastError         *string       `json:"last_error,omitempty"`
+	SentAt            *time.Time    `json:"sent_at,omitempty"`
+	TelegramMessageID *int          `json:"telegram_message_id,omitempty"` // set once sent
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// InlineButton is a button under an outbound message. It opens URL or sends
+// CallbackData back to the bot.
+type InlineButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+// InlineButtons holds the rows of buttons sent with an outbound message
+type InlineButtons [][]InlineButton
+
+// ApprovalNotification records the message an admin was sent about a pending user,
+// so each admin is told once and the message can be updated after someone acts
+type ApprovalNotification struct {
+	ID          int64     `json:"id" gorm:"primaryKey"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_approval_notifications_user_chat"`
+	AdminChatID int64     `json:"admin_chat_id" gorm:"not null;uniqueIndex:idx_approval_notifications_user_chat"`
+	MessageID   int       `json:"message_id"` // 0 until the message is sent
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // Scan implements the sql.Scanner interface for JSONB
 func (sp *SubscriptionPreferences) Scan(value interface{}) error {
 	if value == nil {
@@ -96,15 +179,64 @@ func (sp *SubscriptionPreferences) Scan(value interface{}) error {
 // Value implements the driver.Valuer interface for JSONB
 func (sp SubscriptionPreferences) Value() (interface{}, error) {
 	// Check if struct is empty by comparing individual fields
-	if sp.Currency == "" && sp.Interval == 0 && len(sp.Keywords) == 0 &&
-		sp.Threshold == 0 && len(sp.Settings) == 0 {
+	if sp.Currency == "" && sp.Interval == 0 && sp.Schedule == "" && sp.QuietHours == "" && len(sp.Keywords) == 0 &&
+		sp.Threshold == 0 && sp.Direction == "" && sp.RearmPercent == 0 && len(sp.Settings) == 0 {
 		return "{}", nil
 	}
 	return json.Marshal(sp)
 }
 
+// Scan implements the sql.Scanner interface for JSONB
+func (ps *PreferenceSchema) Scan(value interface{}) error {
+	if value == nil {
+		*ps = PreferenceSchema{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, ps)
+}
+
+// Value implements the driver.Valuer interface for JSONB
+func (ps PreferenceSchema) Value() (interface{}, error) {
+	if len(ps.Properties) == 0 && len(ps.Required) == 0 {
+		return "{}", nil
+	}
+	return json.Marshal(ps)
+}
+
+// Scan implements the sql.Scanner interface for JSONB
+func (b *InlineButtons) Scan(value interface{}) error {
+	if value == nil {
+		*b = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, b)
+}
+
+// Value implements the driver.Valuer interface for JSONB
+func (b InlineButtons) Value() (interface{}, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(b)
+}
+
 // TableName methods for GORM
-func (User) TableName() string             { return "users" }
-func (NotificationType) TableName() string { return "notification_types" }
-func (Subscription) TableName() string     { return "subscriptions" }
-func (NotificationLog) TableName() string  { return "notification_logs" }
+func (User) TableName() string                 { return "users" }
+func (NotificationType) TableName() string     { return "notification_types" }
+func (Subscription) TableName() string         { return "subscriptions" }
+func (NotificationLog) TableName() string      { return "notification_logs" }
+func (PriceAlertState) TableName() string      { return "price_alert_states" }
+func (OutboundMessage) TableName() string      { return "outbound_messages" }
+func (ApprovalNotification) TableName() string { return "approval_notifications" }
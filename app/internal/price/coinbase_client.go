@@ -0,0 +1,103 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCoinbaseBaseURL is the public Coinbase API endpoint
+const DefaultCoinbaseBaseURL = "https://api.coinbase.com"
+
+// CoinbaseConfig configures the Coinbase price client
+type CoinbaseConfig struct {
+	BaseURL string
+	Timeout time.Duration
+}
+
+// CoinbaseClient fetches spot prices from the Coinbase v2 prices API
+type CoinbaseClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// spotPriceResponse mirrors the body of GET /v2/prices/{pair}/spot
+type spotPriceResponse struct {
+	Data struct {
+		Amount   string `json:"amount"`
+		Base     string `json:"base"`
+		Currency string `json:"currency"`
+	} `json:"data"`
+	Errors []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewCoinbaseClient creates a new Coinbase price client
+func NewCoinbaseClient(config CoinbaseConfig) *CoinbaseClient {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultCoinbaseBaseURL
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &CoinbaseClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *CoinbaseClient) GetSpotPrice(ctx context.Context, base, quote string) (float64, error) {
+	base = strings.ToUpper(base)
+	quote = strings.ToUpper(quote)
+	if quote == "" {
+		quote = DefaultQuoteCurrency
+	}
+
+	url := fmt.Sprintf("%s/v2/prices/%s-%s/spot", c.baseURL, base, quote)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create price request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s-%s price: %w", base, quote, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusBadRequest:
+		return 0, &UnsupportedPairError{Base: base, Quote: quote}
+	case resp.StatusCode != http.StatusOK:
+		// Error bodies are not always JSON, e.g. from a proxy; fall back to the status text
+		message := http.StatusText(resp.StatusCode)
+		var body spotPriceResponse
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && len(body.Errors) > 0 {
+			message = body.Errors[0].Message
+		}
+		return 0, fmt.Errorf("price API returned status %d: %s", resp.StatusCode, message)
+	}
+
+	var body spotPriceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode price response: %w", err)
+	}
+
+	amount, err := strconv.ParseFloat(body.Data.Amount, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price amount %q: %w", body.Data.Amount, err)
+	}
+
+	return amount, nil
+}
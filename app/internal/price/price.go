@@ -0,0 +1,112 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultQuoteCurrency is the currency prices are quoted in when none is given
+const DefaultQuoteCurrency = "USD"
+
+// Client fetches spot prices for currency pairs
+type Client interface {
+	// GetSpotPrice returns the current price of base expressed in quote
+	GetSpotPrice(ctx context.Context, base, quote string) (float64, error)
+}
+
+// UnsupportedPairError is returned when the price source does not know a currency pair
+type UnsupportedPairError struct {
+	Base  string
+	Quote string
+}
+
+func (e *UnsupportedPairError) Error() string {
+	return fmt.Sprintf("currency pair %s-%s is not supported", e.Base, e.Quote)
+}
+
+// IsUnsupportedPair reports whether err is caused by an unsupported currency pair
+func IsUnsupportedPair(err error) bool {
+	var pairErr *UnsupportedPairError
+	return errors.As(err, &pairErr)
+}
+
+// pairKey normalises a currency pair into a cache key
+func pairKey(base, quote string) string {
+	return strings.ToUpper(base) + "-" + strings.ToUpper(quote)
+}
+
+type cacheContextKey struct{}
+
+// runCache holds prices fetched during a single dispatch run
+type runCache struct {
+	prices map[string]*cachedPrice
+	mutex  sync.Mutex
+}
+
+// cachedPrice is a pair's fetch in a run; done is closed once price and err are set
+type cachedPrice struct {
+	done  chan struct{}
+	price float64
+	err   error
+}
+
+// WithRunCache returns a context in which prices are fetched at most once per pair.
+// Dispatch runs wrap their context with it so every subscription in the run
+// sees the same price and the upstream API is called once per currency.
+func WithRunCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheContextKey{}, &runCache{
+		prices: make(map[string]*cachedPrice),
+	})
+}
+
+// CachingClient wraps a Client and reuses prices stored in the run cache
+type CachingClient struct {
+	client Client
+}
+
+// NewCachingClient creates a client that honours the run cache in the context
+func NewCachingClient(client Client) *CachingClient {
+	return &CachingClient{client: client}
+}
+
+func (c *CachingClient) GetSpotPrice(ctx context.Context, base, quote string) (float64, error) {
+	cache, ok := ctx.Value(cacheContextKey{}).(*runCache)
+	if !ok {
+		return c.client.GetSpotPrice(ctx, base, quote)
+	}
+
+	key := pairKey(base, quote)
+
+	// Only one fetch per pair is in flight; callers for the same pair wait for it,
+	// callers for other pairs are not held up
+	cache.mutex.Lock()
+	entry, found := cache.prices[key]
+	if !found {
+		entry = &cachedPrice{done: make(chan struct{})}
+		cache.prices[key] = entry
+	}
+	cache.mutex.Unlock()
+
+	if found {
+		select {
+		case <-entry.done:
+			return entry.price, entry.err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	entry.price, entry.err = c.client.GetSpotPrice(ctx, base, quote)
+	if entry.err != nil {
+		// Waiting callers share the error; later ones try again
+		cache.mutex.Lock()
+		delete(cache.prices, key)
+		cache.mutex.Unlock()
+	}
+	close(entry.done)
+
+	return entry.price, entry.err
+}
@@ -0,0 +1,123 @@
+// Package pricetest provides an in-process fake of the Coinbase prices API
+package pricetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is a fake Coinbase prices API backed by httptest
+type Server struct {
+	*httptest.Server
+
+	prices   map[string]float64
+	delays   map[string]time.Duration
+	failures map[string]failure
+	requests map[string]int
+	mutex    sync.Mutex
+}
+
+// failure is a canned error response
+type failure struct {
+	status int
+	body   string
+}
+
+// NewServer starts a fake API serving the given prices keyed by pair, e.g. "BTC-USD"
+func NewServer(prices map[string]float64) *Server {
+	s := &Server{
+		prices:   make(map[string]float64),
+		delays:   make(map[string]time.Duration),
+		failures: make(map[string]failure),
+		requests: make(map[string]int),
+	}
+	for pair, price := range prices {
+		s.prices[strings.ToUpper(pair)] = price
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/prices/", s.handleSpotPrice)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// SetPrice changes the price returned for a pair
+func (s *Server) SetPrice(pair string, price float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.prices[strings.ToUpper(pair)] = price
+}
+
+// SetDelay makes requests for a pair take at least delay
+func (s *Server) SetDelay(pair string, delay time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.delays[strings.ToUpper(pair)] = delay
+}
+
+// SetFailure makes requests for a pair answer with status and a raw body
+func (s *Server) SetFailure(pair string, status int, body string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures[strings.ToUpper(pair)] = failure{status: status, body: body}
+}
+
+// Requests returns how many times a pair has been requested
+func (s *Server) Requests(pair string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests[strings.ToUpper(pair)]
+}
+
+func (s *Server) handleSpotPrice(w http.ResponseWriter, r *http.Request) {
+	// Path format: /v2/prices/{BASE}-{QUOTE}/spot
+	path := strings.TrimPrefix(r.URL.Path, "/v2/prices/")
+	pair, ok := strings.CutSuffix(path, "/spot")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	pair = strings.ToUpper(pair)
+
+	s.mutex.Lock()
+	s.requests[pair]++
+	price, found := s.prices[pair]
+	delay := s.delays[pair]
+	failed, fails := s.failures[pair]
+	s.mutex.Unlock()
+
+	time.Sleep(delay)
+
+	if fails {
+		w.WriteHeader(failed.status)
+		fmt.Fprint(w, failed.body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]string{
+				{"id": "not_found", "message": "Invalid currency"},
+			},
+		})
+		return
+	}
+
+	base, quote, _ := strings.Cut(pair, "-")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]string{
+			"amount":   fmt.Sprintf("%.2f", price),
+			"base":     base,
+			"currency": quote,
+		},
+	})
+}
@@ -0,0 +1,190 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute hour day-of-month month day-of-week
+type Cron struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// Standard cron matches either day field when both are restricted
+	domRestricted bool
+	dowRestricted bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors are shorthands for common schedules
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron parses a cron expression such as "0 7 * * 1-5" or "0 8,18 * * *"
+func ParseCron(expr string) (*Cron, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day month weekday)", expr)
+	}
+
+	cron := &Cron{}
+	var err error
+	if cron.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if cron.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if cron.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if cron.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if cron.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Sunday may be written as 0 or 7
+	if cron.dow&(1<<7) != 0 {
+		cron.dow |= 1
+	}
+
+	// As in Vixie cron, a field starting with '*' (such as */2) does not restrict the day
+	cron.domRestricted = !strings.HasPrefix(fields[2], "*")
+	cron.dowRestricted = !strings.HasPrefix(fields[4], "*")
+	return cron, nil
+}
+
+// Next returns the first time after t that matches the expression, in t's location.
+// It returns the zero time if nothing matches within five years (e.g. "0 0 31 2 *").
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay applies cron's day-of-month / day-of-week rules
+func (c *Cron) matchesDay(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// parse turns a field such as "*/15", "1-5" or "mon,wed,fri" into a bit set
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+			}
+			step = n
+		}
+
+		start, end := f.min, f.max
+		if rangePart != "*" {
+			low, high, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			if start, err = f.value(low); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = f.value(high); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/15" means every 15 starting at 5
+				end = f.max
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangePart, f.name)
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single number or name within the field's bounds
+func (f cronField) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field (allowed %d-%d)", s, f.name, f.min, f.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,139 @@
+// Package schedule decides when a subscription is due: fixed intervals,
+// cron expressions and quiet hours, evaluated in the user's time zone.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the zone database so user time zones work in minimal containers
+	_ "time/tzdata"
+)
+
+// QuietHours is a daily window, possibly spanning midnight, in which nothing is sent
+type QuietHours struct {
+	start int // minutes after midnight
+	end   int
+}
+
+// ParseQuietHours parses a window such as "23:00-07:00"
+func ParseQuietHours(value string) (QuietHours, error) {
+	startPart, endPart, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("quiet hours %q must look like 23:00-07:00", value)
+	}
+
+	start, err := parseClock(startPart)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	end, err := parseClock(endPart)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("quiet hours %q start and end must differ", value)
+	}
+
+	return QuietHours{start: start, end: end}, nil
+}
+
+// Contains reports whether t falls inside the window, using t's location
+func (q QuietHours) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	// Window wraps past midnight
+	return minute >= q.start || minute < q.end
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(value string) (int, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// LoadLocation resolves an IANA time zone name; an empty name means UTC
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// Rules describe when a single subscription should be notified
+type Rules struct {
+	// Interval sends every N after the last notification; ignored when Cron is set
+	Interval time.Duration
+	// Cron is a cron expression evaluated in Location
+	Cron string
+	// QuietHours is a window such as "23:00-07:00" in which nothing is sent
+	QuietHours string
+	// Location is the user's time zone
+	Location *time.Location
+}
+
+// Validate checks that the cron expression and quiet hours parse
+func (r Rules) Validate() error {
+	if r.Cron != "" {
+		if _, err := ParseCron(r.Cron); err != nil {
+			return err
+		}
+	}
+	if r.QuietHours != "" {
+		if _, err := ParseQuietHours(r.QuietHours); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsDue reports whether a subscription last notified at lastNotified (nil if never)
+// and created at createdAt should be notified at now.
+func (r Rules) IsDue(now time.Time, lastNotified *time.Time, createdAt time.Time) (bool, error) {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+
+	if r.QuietHours != "" {
+		quiet, err := ParseQuietHours(r.QuietHours)
+		if err != nil {
+			return false, err
+		}
+		if quiet.Contains(local) {
+			return false, nil
+		}
+	}
+
+	if r.Cron != "" {
+		cron, err := ParseCron(r.Cron)
+		if err != nil {
+			return false, err
+		}
+
+		// Due once a fire time has passed since the last send; a fire missed
+		// during quiet hours or downtime is delivered once, not repeatedly
+		since := createdAt
+		if lastNotified != nil {
+			since = *lastNotified
+		}
+		next := cron.Next(since.In(loc))
+		return !next.IsZero() && !next.After(local), nil
+	}
+
+	if lastNotified == nil {
+		return true, nil
+	}
+	return !lastNotified.Add(r.Interval).After(now), nil
+}
@@ -2,20 +2,28 @@ package scheduler
 
 import (
 	"context"
+	"go-messaging/repository"
 	"go-messaging/service"
 	"log/slog"
 	"time"
 )
 
+// cleanupLockName is the advisory lock that keeps cleanup to one instance at a time
+const cleanupLockName = "scheduler:cleanup_pending_users"
+
 type CleanupScheduler struct {
 	adminService service.AdminServiceInterface
+	jobLocks     repository.JobLockRepository
 	ticker       *time.Ticker
 	done         chan bool
 }
 
-func NewCleanupScheduler(adminService service.AdminServiceInterface) *CleanupScheduler {
+// NewCleanupScheduler creates a cleanup scheduler. With a non-nil jobLocks only one
+// instance runs the cleanup at a time; the others skip that round.
+func NewCleanupScheduler(adminService service.AdminServiceInterface, jobLocks repository.JobLockRepository) *CleanupScheduler {
 	return &CleanupScheduler{
 		adminService: adminService,
+		jobLocks:     jobLocks,
 		done:         make(chan bool),
 	}
 }
@@ -56,6 +64,26 @@ func (s *CleanupScheduler) runCleanup() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
+	if s.jobLocks == nil {
+		s.cleanup(ctx)
+		return
+	}
+
+	acquired, err := s.jobLocks.RunExclusive(ctx, cleanupLockName, func(ctx context.Context) error {
+		s.cleanup(ctx)
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to acquire cleanup lock", "error", err)
+		return
+	}
+	if !acquired {
+		slog.Debug("Skipping scheduled cleanup, another instance is running it")
+	}
+}
+
+// cleanup deletes stale pending users
+func (s *CleanupScheduler) cleanup(ctx context.Context) {
 	slog.Info("Running scheduled cleanup of pending users")
 
 	count, err := s.adminService.CleanupPendingUsers(ctx)
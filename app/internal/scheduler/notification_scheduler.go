@@ -3,58 +3,184 @@ package scheduler
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"go-messaging/service"
 )
 
+// DefaultReloadInterval is how often the schedule is re-read from the database
+const DefaultReloadInterval = time.Minute
+
 type NotificationScheduler struct {
-	dispatchService service.NotificationDispatchService
-	schedule        map[string]int // notification type -> interval in minutes
+	dispatchService         service.NotificationDispatchService
+	notificationTypeService service.NotificationTypeService
+	subscriptionService     service.SubscriptionService
+	reloadInterval          time.Duration
+	reload                  chan struct{}
+
+	mutex   sync.Mutex
+	running map[string]*typeSchedule // notification type code -> running ticker
+}
+
+// typeSchedule is the ticker goroutine for one notification type
+type typeSchedule struct {
+	interval time.Duration
+	cancel   context.CancelFunc
 }
 
-func NewNotificationScheduler(dispatchService service.NotificationDispatchService) *NotificationScheduler {
+func NewNotificationScheduler(
+	dispatchService service.NotificationDispatchService,
+	notificationTypeService service.NotificationTypeService,
+	subscriptionService service.SubscriptionService,
+) *NotificationScheduler {
 	return &NotificationScheduler{
-		dispatchService: dispatchService,
-		schedule: map[string]int{
-			"coinbase":    1, // Every 1 minute
-			"news":        2, // Every 2 minutes
-			"weather":     4, // Every 4 minutes
-			"price_alert": 5, // Every 5 minutes for testing (change back to 5+ for production)
-			"custom":      6, // Every 6 minutes
-		},
+		dispatchService:         dispatchService,
+		notificationTypeService: notificationTypeService,
+		subscriptionService:     subscriptionService,
+		reloadInterval:          DefaultReloadInterval,
+		reload:                  make(chan struct{}, 1),
+		running:                 make(map[string]*typeSchedule),
+	}
+}
+
+// SetReloadInterval changes how often the schedule is re-read from the database
+func (ns *NotificationScheduler) SetReloadInterval(interval time.Duration) {
+	ns.reloadInterval = interval
+}
+
+// TriggerReload asks the scheduler to re-read the schedule without waiting for the next reload tick
+func (ns *NotificationScheduler) TriggerReload() {
+	select {
+	case ns.reload <- struct{}{}:
+	default: // a reload is already pending
 	}
 }
 
-// SetSchedule allows customizing the notification schedule
-func (ns *NotificationScheduler) SetSchedule(schedule map[string]int) {
-	ns.schedule = schedule
+// Schedule returns the interval currently used for each scheduled notification type
+func (ns *NotificationScheduler) Schedule() map[string]time.Duration {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	schedule := make(map[string]time.Duration, len(ns.running))
+	for code, running := range ns.running {
+		schedule[code] = running.interval
+	}
+	return schedule
 }
 
 // Start begins the notification scheduling process
 func (ns *NotificationScheduler) Start(ctx context.Context) {
 	log.Println("📡 Starting notification scheduler...")
 
-	// Start individual schedulers for each notification type
-	for notificationType, intervalMinutes := range ns.schedule {
-		go ns.runNotificationSchedule(ctx, notificationType, intervalMinutes)
+	if err := ns.Reload(ctx); err != nil {
+		log.Printf("❌ Failed to load notification schedule: %v", err)
+	}
+
+	reloadTicker := time.NewTicker(ns.reloadInterval)
+	defer reloadTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			ns.stopAll()
+			log.Println("📡 Notification scheduler stopped")
+			return
+		case <-reloadTicker.C:
+		case <-ns.reload:
+		}
+
+		if err := ns.Reload(ctx); err != nil {
+			log.Printf("❌ Failed to reload notification schedule: %v", err)
+		}
+	}
+}
+
+// Reload reads the active notification types and starts, restarts or stops tickers to match
+func (ns *NotificationScheduler) Reload(ctx context.Context) error {
+	desired, err := ns.loadSchedule(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
-	log.Println("📡 Notification scheduler stopped")
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	// Stop tickers for deactivated types and types whose interval changed
+	for code, running := range ns.running {
+		interval, ok := desired[code]
+		if ok && interval == running.interval {
+			continue
+		}
+		running.cancel()
+		delete(ns.running, code)
+		if !ok {
+			log.Printf("⏹️ Stopped %s notification scheduler (type no longer active)", code)
+		}
+	}
+
+	for code, interval := range desired {
+		if _, ok := ns.running[code]; ok {
+			continue
+		}
+		typeCtx, cancel := context.WithCancel(ctx)
+		ns.running[code] = &typeSchedule{interval: interval, cancel: cancel}
+		go ns.runNotificationSchedule(typeCtx, code, interval)
+	}
+
+	return nil
+}
+
+// loadSchedule builds the tick interval for each active notification type the dispatcher
+// generates. A type ticks at its default interval, or faster if an active subscriber
+// asked for a shorter one. Externally delivered types are left out.
+func (ns *NotificationScheduler) loadSchedule(ctx context.Context) (map[string]time.Duration, error) {
+	types, err := ns.notificationTypeService.GetActiveTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	minIntervals, err := ns.subscriptionService.GetMinIntervals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make(map[string]time.Duration, len(types))
+	for _, notificationType := range types {
+		if !ns.dispatchService.Dispatches(notificationType.Code) {
+			continue
+		}
+		minutes := notificationType.DefaultIntervalMinutes
+		if subscriberMinutes, ok := minIntervals[notificationType.ID]; ok && subscriberMinutes > 0 && subscriberMinutes < minutes {
+			minutes = subscriberMinutes
+		}
+		if minutes <= 0 {
+			minutes = 1
+		}
+		schedule[notificationType.Code] = time.Duration(minutes) * time.Minute
+	}
+
+	return schedule, nil
+}
+
+// stopAll stops every running ticker
+func (ns *NotificationScheduler) stopAll() {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	for code, running := range ns.running {
+		running.cancel()
+		delete(ns.running, code)
+	}
 }
 
 // runNotificationSchedule runs a scheduler for a specific notification type
-func (ns *NotificationScheduler) runNotificationSchedule(ctx context.Context, notificationType string, intervalMinutes int) {
-	log.Printf("⏰ Starting %s notification scheduler (every %d minutes)", notificationType, intervalMinutes)
+func (ns *NotificationScheduler) runNotificationSchedule(ctx context.Context, notificationType string, interval time.Duration) {
+	log.Printf("⏰ Starting %s notification scheduler (every %s)", notificationType, interval)
 
-	ticker := time.NewTicker(time.Duration(intervalMinutes) * time.Minute)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	// For development: Don't run immediately on startup, wait for first interval
-	log.Printf("⏳ Waiting %d minutes before first %s notification...", intervalMinutes, notificationType)
-
 	for {
 		select {
 		case <-ctx.Done():
@@ -62,10 +188,12 @@ func (ns *NotificationScheduler) runNotificationSchedule(ctx context.Context, no
 			return
 		case <-ticker.C:
 			log.Printf("🔔 Time to dispatch %s notifications!", notificationType)
-			if err := ns.dispatchService.DispatchNotification(ctx, notificationType); err != nil {
+			result, err := ns.dispatchService.DispatchNotification(ctx, notificationType)
+			if err != nil {
 				log.Printf("❌ Failed to dispatch %s notifications: %v", notificationType, err)
 			} else {
-				log.Printf("✅ Dispatched %s notifications", notificationType)
+				log.Printf("✅ Dispatched %s notifications: %d sent, %d failed, %d skipped in %s",
+					notificationType, result.Sent, result.Failed, result.Skipped, result.Duration.Round(time.Millisecond))
 			}
 		}
 	}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"go-messaging/service"
+	"log/slog"
+	"time"
+)
+
+// OutboxScheduler drains the outbound message queue
+type OutboxScheduler struct {
+	outboxService service.OutboxService
+	interval      time.Duration
+	batchSize     int
+	ticker        *time.Ticker
+	done          chan bool
+}
+
+func NewOutboxScheduler(outboxService service.OutboxService, interval time.Duration, batchSize int) *OutboxScheduler {
+	return &OutboxScheduler{
+		outboxService: outboxService,
+		interval:      interval,
+		batchSize:     batchSize,
+		done:          make(chan bool),
+	}
+}
+
+// Start begins draining the outbox on every tick
+func (s *OutboxScheduler) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(s.interval)
+
+	go func() {
+		slog.Info("Starting outbox scheduler", "interval", s.interval, "batch_size", s.batchSize)
+
+		for {
+			select {
+			case <-s.done:
+				slog.Info("Outbox scheduler stopped")
+				return
+			case <-s.ticker.C:
+				s.drain(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the outbox scheduler
+func (s *OutboxScheduler) Stop() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	s.done <- true
+}
+
+// drain sends full batches until the queue has no more due messages
+func (s *OutboxScheduler) drain(ctx context.Context) {
+	// Pick up messages left half-sent by this or any other instance that crashed
+	if count, err := s.outboxService.RecoverStale(ctx); err != nil {
+		slog.Error("Failed to recover stale outbound messages", "error", err)
+	} else if count > 0 {
+		slog.Info("Recovered stale outbound messages", "count", count)
+	}
+
+	for ctx.Err() == nil {
+		count, err := s.outboxService.ProcessDue(ctx, s.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Failed to process outbox", "error", err)
+			}
+			return
+		}
+		if count < s.batchSize {
+			return
+		}
+	}
+}
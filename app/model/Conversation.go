@@ -0,0 +1,82 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// CONVERSATION_TIMEOUT is how long the bot waits for a reply before forgetting a dialogue
+const CONVERSATION_TIMEOUT = 5 * time.Minute
+
+// Conversation is a multi-step dialogue the bot is having with one user in one chat
+type Conversation struct {
+	Flow             string // e.g. 'settings'
+	NotificationType string
+	Field            string // preference key the bot is waiting for
+	ExpiresAt        time.Time
+}
+
+type conversationKey struct {
+	chatID int64
+	userID int64
+}
+
+// ConversationStore keeps per-chat, per-user dialogue state in memory
+type ConversationStore struct {
+	conversations map[conversationKey]*Conversation
+	timeout       time.Duration
+	mutex         sync.Mutex
+}
+
+// NewConversationStore creates a store whose conversations expire after timeout
+func NewConversationStore(timeout time.Duration) *ConversationStore {
+	return &ConversationStore{
+		conversations: make(map[conversationKey]*Conversation),
+		timeout:       timeout,
+	}
+}
+
+// Start begins or replaces the conversation for a user in a chat
+func (s *ConversationStore) Start(chatID, userID int64, conversation Conversation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeExpired(time.Now())
+	conversation.ExpiresAt = time.Now().Add(s.timeout)
+	s.conversations[conversationKey{chatID, userID}] = &conversation
+}
+
+// Get returns the active conversation for a user in a chat, or nil if there is none or it timed out
+func (s *ConversationStore) Get(chatID, userID int64) *Conversation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	conversation, ok := s.conversations[conversationKey{chatID, userID}]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(conversation.ExpiresAt) {
+		delete(s.conversations, conversationKey{chatID, userID})
+		return nil
+	}
+
+	copied := *conversation
+	return &copied
+}
+
+// End forgets the conversation for a user in a chat
+func (s *ConversationStore) End(chatID, userID int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.conversations, conversationKey{chatID, userID})
+}
+
+// removeExpired drops timed-out conversations; the caller must hold the mutex
+func (s *ConversationStore) removeExpired(now time.Time) {
+	for key, conversation := range s.conversations {
+		if now.After(conversation.ExpiresAt) {
+			delete(s.conversations, key)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Outbound limits published by Telegram for bots
+const (
+	OUTBOUND_GLOBAL_PER_SECOND = 30               // 30 messages per second across all chats
+	OUTBOUND_CHAT_INTERVAL     = 1 * time.Second  // 1 message per second to the same chat
+	OUTBOUND_GROUP_INTERVAL    = time.Minute / 20 // 20 messages per minute to the same group
+	OUTBOUND_IDLE_CHAT_TTL     = 10 * time.Minute // forget chats that have been idle this long
+	OUTBOUND_PRUNE_INTERVAL    = 5 * time.Minute  // how often idle chats are forgotten
+)
+
+// ChatPausedError is returned instead of waiting when Telegram has paused a chat.
+// The send can be retried once Until has passed.
+type ChatPausedError struct {
+	ChatID int64
+	Until  time.Time
+}
+
+func (e *ChatPausedError) Error() string {
+	return fmt.Sprintf("chat %d is paused by Telegram until %s", e.ChatID, e.Until.Format(time.RFC3339))
+}
+
+// OutboundLimiter spaces out messages the bot sends so Telegram's flood limits are never hit
+type OutboundLimiter struct {
+	global        *Throttle
+	chatInterval  time.Duration
+	groupInterval time.Duration
+
+	chats     map[int64]*chatLimiter
+	lastPrune time.Time
+	mutex     sync.Mutex
+}
+
+// chatLimiter tracks the next free send slot for one chat
+type chatLimiter struct {
+	next        time.Time
+	pausedUntil time.Time
+}
+
+// NewOutboundLimiter creates a limiter using Telegram's published limits
+func NewOutboundLimiter() *OutboundLimiter {
+	return NewOutboundLimiterWithLimits(OUTBOUND_GLOBAL_PER_SECOND, OUTBOUND_CHAT_INTERVAL, OUTBOUND_GROUP_INTERVAL)
+}
+
+// NewOutboundLimiterWithLimits creates a limiter with custom limits
+func NewOutboundLimiterWithLimits(globalPerSecond int, chatInterval, groupInterval time.Duration) *OutboundLimiter {
+	return &OutboundLimiter{
+		global:        NewThrottle(globalPerSecond),
+		chatInterval:  chatInterval,
+		groupInterval: groupInterval,
+		chats:         make(map[int64]*chatLimiter),
+		lastPrune:     time.Now(),
+	}
+}
+
+// Wait blocks until a message may be sent to chatID or the context is cancelled
+func (l *OutboundLimiter) Wait(ctx context.Context, chatID int64) error {
+	// A chat may be paused while we sleep, so re-check after every reservation
+	for {
+		slot, pausedUntil := l.reserve(chatID)
+		if err := sleepUntil(ctx, slot); err != nil {
+			l.unreserve(chatID, slot)
+			return err
+		}
+		if !time.Now().Before(pausedUntil) {
+			break
+		}
+	}
+
+	return l.global.Wait(ctx)
+}
+
+// Pause stops sends to chatID for the given duration, e.g. after a 429 with retry_after
+func (l *OutboundLimiter) Pause(chatID int64, duration time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	chat := l.chat(chatID)
+	until := time.Now().Add(duration)
+	if until.After(chat.pausedUntil) {
+		chat.pausedUntil = until
+	}
+	if until.After(chat.next) {
+		chat.next = until
+	}
+}
+
+// PausedUntil returns when a pause on chatID ends; it is in the past if there is none
+func (l *OutboundLimiter) PausedUntil(chatID int64) time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if chat, exists := l.chats[chatID]; exists {
+		return chat.pausedUntil
+	}
+	return time.Time{}
+}
+
+// reserve claims the next free slot for chatID
+func (l *OutboundLimiter) reserve(chatID int64) (time.Time, time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	chat := l.chat(chatID)
+	slot := chat.next
+	if slot.Before(now) {
+		slot = now
+	}
+	chat.next = slot.Add(l.intervalFor(chatID))
+
+	return slot, chat.pausedUntil
+}
+
+// unreserve gives back a slot that was never used, unless a later slot has been
+// reserved since, so a cancelled wait does not delay the chat's next message
+func (l *OutboundLimiter) unreserve(chatID int64, slot time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	chat, exists := l.chats[chatID]
+	if !exists || !chat.next.Equal(slot.Add(l.intervalFor(chatID))) {
+		return
+	}
+	chat.next = slot
+	if chat.next.Before(chat.pausedUntil) {
+		chat.next = chat.pausedUntil
+	}
+}
+
+// chat returns the limiter state for chatID; the caller must hold the mutex
+func (l *OutboundLimiter) chat(chatID int64) *chatLimiter {
+	chat, exists := l.chats[chatID]
+	if !exists {
+		chat = &chatLimiter{}
+		l.chats[chatID] = chat
+	}
+	return chat
+}
+
+// intervalFor returns the minimum spacing between messages to a chat.
+// Group, supergroup and channel IDs are negative in the Bot API.
+func (l *OutboundLimiter) intervalFor(chatID int64) time.Duration {
+	if chatID < 0 {
+		return l.groupInterval
+	}
+	return l.chatInterval
+}
+
+// prune forgets idle chats; the caller must hold the mutex
+func (l *OutboundLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < OUTBOUND_PRUNE_INTERVAL {
+		return
+	}
+	l.lastPrune = now
+
+	for chatID, chat := range l.chats {
+		if now.Sub(chat.next) > OUTBOUND_IDLE_CHAT_TTL && now.After(chat.pausedUntil) {
+			delete(l.chats, chatID)
+		}
+	}
+}
+
+// sleepUntil blocks until t or until the context is cancelled
+func sleepUntil(ctx context.Context, t time.Time) error {
+	delay := time.Until(t)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
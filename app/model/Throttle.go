@@ -0,0 +1,56 @@
+package model
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Throttle spaces out events so that no more than a fixed number happen per second
+type Throttle struct {
+	interval time.Duration
+	next     time.Time
+	mutex    sync.Mutex
+}
+
+// NewThrottle creates a throttle allowing perSecond events per second.
+// A non-positive rate disables throttling.
+func NewThrottle(perSecond int) *Throttle {
+	t := &Throttle{}
+	if perSecond > 0 {
+		t.interval = time.Second / time.Duration(perSecond)
+	}
+	return t
+}
+
+// Wait blocks until the caller may proceed or the context is cancelled
+func (t *Throttle) Wait(ctx context.Context) error {
+	if t == nil || t.interval == 0 {
+		return ctx.Err()
+	}
+
+	// Reserve the next free slot, then sleep until it arrives
+	t.mutex.Lock()
+	now := time.Now()
+	slot := t.next
+	if slot.Before(now) {
+		slot = now
+	}
+	t.next = slot.Add(t.interval)
+	t.mutex.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"go-messaging/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormApprovalNotificationRepository implements ApprovalNotificationRepository using GORM
+type GormApprovalNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewApprovalNotificationRepository creates a new approval notification repository
+func NewApprovalNotificationRepository(db *gorm.DB) ApprovalNotificationRepository {
+	return &GormApprovalNotificationRepository{db: db}
+}
+
+func (r *GormApprovalNotificationRepository) Claim(ctx context.Context, userID uuid.UUID, adminChatID int64) (bool, error) {
+	notification := &entity.ApprovalNotification{UserID: userID, AdminChatID: adminChatID}
+
+	// The unique (user_id, admin_chat_id) index makes a second claim a no-op
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *GormApprovalNotificationRepository) SetMessageID(ctx context.Context, userID uuid.UUID, adminChatID int64, messageID int) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.ApprovalNotification{}).
+		Where("user_id = ? AND admin_chat_id = ?", userID, adminChatID).
+		Update("message_id", messageID).Error
+}
+
+func (r *GormApprovalNotificationRepository) Release(ctx context.Context, userID uuid.UUID, adminChatID int64) error {
+	return r.db.WithContext(ctx).
+		Delete(&entity.ApprovalNotification{}, "user_id = ? AND admin_chat_id = ?", userID, adminChatID).Error
+}
+
+func (r *GormApprovalNotificationRepository) TakeByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.ApprovalNotification, error) {
+	var notifications []*entity.ApprovalNotification
+
+	// Deleting with RETURNING hands each message to exactly one caller when admins act at once
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Delete(&notifications).Error
+	return notifications, err
+}
@@ -31,8 +31,13 @@ type UserRepository interface {
 	// Admin-specific methods
 	GetUsersByApprovalStatus(ctx context.Context, status string) ([]entity.User, error)
 	GetUsersByApprovalStatusWithLimit(ctx context.Context, status string, limit int) ([]entity.User, error)
+	// GetUsersByApprovalStatusPage retrieves one page of users with a status, newest first
+	GetUsersByApprovalStatusPage(ctx context.Context, status string, offset, limit int) ([]entity.User, error)
+	// SearchByName retrieves users whose username, first or last name contains name
+	SearchByName(ctx context.Context, name string, limit int) ([]entity.User, error)
 	CountUsersByApprovalStatus(ctx context.Context, status string) (int64, error)
 	CountUsersByRole(ctx context.Context, role string) (int64, error)
+	GetUsersByRoleAndApprovalStatus(ctx context.Context, role, status string) ([]entity.User, error)
 	CountAll(ctx context.Context) (int64, error)
 	DeletePendingUsersOlderThan(ctx context.Context, duration time.Duration) (int, error)
 }
@@ -59,6 +64,9 @@ type NotificationTypeRepository interface {
 
 	// Delete deletes a notification type by ID
 	Delete(ctx context.Context, id int) error
+
+	// DeleteWithSubscriptions deletes a notification type and every subscription to it
+	DeleteWithSubscriptions(ctx context.Context, id int) error
 }
 
 // SubscriptionRepository defines the interface for subscription data access
@@ -75,26 +83,60 @@ type SubscriptionRepository interface {
 	// GetByUserAndType retrieves a subscription by user ID and notification type ID
 	GetByUserAndType(ctx context.Context, userID uuid.UUID, notificationTypeID int) (*entity.Subscription, error)
 
+	// GetByChatAndType retrieves the subscription of a chat to a notification type
+	GetByChatAndType(ctx context.Context, chatID int64, notificationTypeID int) (*entity.Subscription, error)
+
 	// GetActiveByChatID retrieves all active subscriptions for a chat
 	GetActiveByChatID(ctx context.Context, chatID int64) ([]*entity.Subscription, error)
 
-	// GetActiveByType retrieves all active subscriptions for a notification type
+	// List retrieves one page of the subscriptions matching a filter, ordered by ID, and
+	// how many match in total
+	List(ctx context.Context, filter SubscriptionFilter, offset, limit int) ([]*entity.Subscription, int64, error)
+
+	// GetActiveByType retrieves all active subscriptions of approved users for a notification type
 	GetActiveByType(ctx context.Context, notificationTypeID int) ([]*entity.Subscription, error)
 
 	// GetDueForNotification retrieves subscriptions that are due for notification
 	GetDueForNotification(ctx context.Context, notificationTypeID int) ([]*entity.Subscription, error)
 
+	// ClaimDueForNotification atomically selects due subscriptions and advances their
+	// last notified timestamp, so concurrent dispatchers never claim the same row.
+	// The returned subscriptions keep their previous LastNotifiedAt.
+	ClaimDueForNotification(ctx context.Context, notificationTypeID int) ([]*entity.Subscription, error)
+
+	// CountActiveByType returns the number of active subscriptions, keyed by
+	// notification type ID; types without any are left out
+	CountActiveByType(ctx context.Context) (map[int]int64, error)
+
+	// GetMinIntervals returns the shortest effective interval in minutes among active
+	// subscriptions, keyed by notification type ID
+	GetMinIntervals(ctx context.Context) (map[int]int, error)
+
 	// Update updates an existing subscription
 	Update(ctx context.Context, subscription *entity.Subscription) error
 
 	// UpdateLastNotified updates the last notified timestamp
 	UpdateLastNotified(ctx context.Context, id int64) error
 
+	// SetLastNotified sets the last notified timestamp, e.g. to undo a claim
+	SetLastNotified(ctx context.Context, id int64, at *time.Time) error
+
 	// Delete deletes a subscription by ID
 	Delete(ctx context.Context, id int64) error
 
 	// DeleteByUserAndType deletes a subscription by user ID and notification type ID
 	DeleteByUserAndType(ctx context.Context, userID uuid.UUID, notificationTypeID int) error
+
+	// DeleteByChatAndType deletes the subscription of a chat to a notification type
+	DeleteByChatAndType(ctx context.Context, chatID int64, notificationTypeID int) error
+}
+
+// SubscriptionFilter narrows a subscription listing; nil fields match everything
+type SubscriptionFilter struct {
+	UserID             *uuid.UUID
+	ChatID             *int64
+	NotificationTypeID *int
+	IsActive           *bool
 }
 
 // NotificationLogRepository defines the interface for notification log data access
@@ -120,3 +162,62 @@ type NotificationLogRepository interface {
 	// CleanupOldLogs deletes logs older than the specified number of days
 	CleanupOldLogs(ctx context.Context, daysOld int) error
 }
+
+// PriceAlertStateRepository defines the interface for price alert state data access
+type PriceAlertStateRepository interface {
+	// GetBySubscriptionID retrieves the alert state for a subscription
+	GetBySubscriptionID(ctx context.Context, subscriptionID int64) (*entity.PriceAlertState, error)
+
+	// Save creates or updates the alert state for a subscription
+	Save(ctx context.Context, state *entity.PriceAlertState) error
+
+	// Delete removes the alert state for a subscription
+	Delete(ctx context.Context, subscriptionID int64) error
+}
+
+// OutboxRepository defines the interface for outbound message queue data access
+type OutboxRepository interface {
+	// Create enqueues a new outbound message
+	Create(ctx context.Context, message *entity.OutboundMessage) error
+
+	// GetByID retrieves an outbound message by ID
+	GetByID(ctx context.Context, id int64) (*entity.OutboundMessage, error)
+
+	// ClaimDue locks up to limit pending messages that are due and marks them as sending
+	ClaimDue(ctx context.Context, limit int) ([]*entity.OutboundMessage, error)
+
+	// Update updates an existing outbound message
+	Update(ctx context.Context, message *entity.OutboundMessage) error
+
+	// ListByStatus retrieves outbound messages with a status, oldest first
+	ListByStatus(ctx context.Context, status string, offset, limit int) ([]*entity.OutboundMessage, error)
+
+	// CountByStatus counts outbound messages with a status
+	CountByStatus(ctx context.Context, status string) (int64, error)
+
+	// ReleaseStale returns messages stuck in sending for longer than duration to pending
+	ReleaseStale(ctx context.Context, duration time.Duration) (int, error)
+}
+
+// JobLockRepository coordinates background jobs across application instances
+type JobLockRepository interface {
+	// RunExclusive runs fn while holding the named lock. If another instance holds it,
+	// fn is not run and false is returned.
+	RunExclusive(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// ApprovalNotificationRepository defines the interface for tracking admin notifications about pending users
+type ApprovalNotificationRepository interface {
+	// Claim records that an admin is being told about a user. It returns false if
+	// that admin was already told, so each admin gets one message per user.
+	Claim(ctx context.Context, userID uuid.UUID, adminChatID int64) (bool, error)
+
+	// SetMessageID stores the ID of the message sent for a claimed notification
+	SetMessageID(ctx context.Context, userID uuid.UUID, adminChatID int64, messageID int) error
+
+	// Release removes a claim whose message could not be sent
+	Release(ctx context.Context, userID uuid.UUID, adminChatID int64) error
+
+	// TakeByUserID removes and returns the notifications sent about a user
+	TakeByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.ApprovalNotification, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// PostgresJobLockRepository implements JobLockRepository with Postgres advisory locks
+type PostgresJobLockRepository struct {
+	db *gorm.DB
+}
+
+// NewJobLockRepository creates a new job lock repository
+func NewJobLockRepository(db *gorm.DB) JobLockRepository {
+	return &PostgresJobLockRepository{db: db}
+}
+
+func (r *PostgresJobLockRepository) RunExclusive(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	acquired := false
+
+	// A transaction-level lock is released on commit or rollback, so a crashed
+	// instance can never leave the job locked
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", name).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		return fn(ctx)
+	})
+
+	return acquired, err
+}
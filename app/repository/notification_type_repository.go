@@ -59,3 +59,12 @@ func (r *GormNotificationTypeRepository) Update(ctx context.Context, notificatio
 func (r *GormNotificationTypeRepository) Delete(ctx context.Context, id int) error {
 	return r.db.WithContext(ctx).Delete(&entity.NotificationType{}, id).Error
 }
+
+func (r *GormNotificationTypeRepository) DeleteWithSubscriptions(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("notification_type_id = ?", id).Delete(&entity.Subscription{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.NotificationType{}, id).Error
+	})
+}
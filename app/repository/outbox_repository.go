@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-messaging/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormOutboxRepository implements OutboxRepository using GORM
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &GormOutboxRepository{db: db}
+}
+
+func (r *GormOutboxRepository) Create(ctx context.Context, message *entity.OutboundMessage) error {
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+func (r *GormOutboxRepository) GetByID(ctx context.Context, id int64) (*entity.OutboundMessage, error) {
+	var message entity.OutboundMessage
+	err := r.db.WithContext(ctx).First(&message, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *GormOutboxRepository) ClaimDue(ctx context.Context, limit int) ([]*entity.OutboundMessage, error) {
+	var messages []*entity.OutboundMessage
+
+	// SKIP LOCKED lets several senders drain the queue without picking the same rows
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entity.OutboundStatusPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		now := time.Now()
+		if err := tx.Model(&entity.OutboundMessage{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": entity.OutboundStatusSending, "locked_at": now}).Error; err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			message.Status = entity.OutboundStatusSending
+			message.LockedAt = &now
+		}
+		return nil
+	})
+
+	return messages, err
+}
+
+func (r *GormOutboxRepository) Update(ctx context.Context, message *entity.OutboundMessage) error {
+	return r.db.WithContext(ctx).Save(message).Error
+}
+
+func (r *GormOutboxRepository) ListByStatus(ctx context.Context, status string, offset, limit int) ([]*entity.OutboundMessage, error) {
+	var messages []*entity.OutboundMessage
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *GormOutboxRepository) CountByStatus(ctx context.Context, status string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.OutboundMessage{}).Where("status = ?", status).Count(&count).Error
+	return count, err
+}
+
+func (r *GormOutboxRepository) ReleaseStale(ctx context.Context, duration time.Duration) (int, error) {
+	cutoffTime := time.Now().Add(-duration)
+	result := r.db.WithContext(ctx).
+		Model(&entity.OutboundMessage{}).
+		Where("status = ? AND locked_at < ?", entity.OutboundStatusSending, cutoffTime).
+		Updates(map[string]interface{}{"status": entity.OutboundStatusPending, "locked_at": nil})
+	return int(result.RowsAffected), result.Error
+}
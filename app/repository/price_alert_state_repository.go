@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-messaging/entity"
+
+	"gorm.io/gorm"
+)
+
+// GormPriceAlertStateRepository implements PriceAlertStateRepository using GORM
+type GormPriceAlertStateRepository struct {
+	db *gorm.DB
+}
+
+// NewPriceAlertStateRepository creates a new price alert state repository
+func NewPriceAlertStateRepository(db *gorm.DB) PriceAlertStateRepository {
+	return &GormPriceAlertStateRepository{db: db}
+}
+
+func (r *GormPriceAlertStateRepository) GetBySubscriptionID(ctx context.Context, subscriptionID int64) (*entity.PriceAlertState, error) {
+	var state entity.PriceAlertState
+	err := r.db.WithContext(ctx).First(&state, "subscription_id = ?", subscriptionID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *GormPriceAlertStateRepository) Save(ctx context.Context, state *entity.PriceAlertState) error {
+	state.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(state).Error
+}
+
+func (r *GormPriceAlertStateRepository) Delete(ctx context.Context, subscriptionID int64) error {
+	return r.db.WithContext(ctx).Delete(&entity.PriceAlertState{}, "subscription_id = ?", subscriptionID).Error
+}
@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	"go-messaging/entity"
+	"go-messaging/internal/schedule"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormSubscriptionRepository implements SubscriptionRepository using GORM
@@ -58,6 +61,19 @@ func (r *GormSubscriptionRepository) GetByUserAndType(ctx context.Context, userI
 	return &subscription, nil
 }
 
+func (r *GormSubscriptionRepository) GetByChatAndType(ctx context.Context, chatID int64, notificationTypeID int) (*entity.Subscription, error) {
+	var subscription entity.Subscription
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("NotificationType").
+		Where("chat_id = ? AND notification_type_id = ?", chatID, notificationTypeID).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
 func (r *GormSubscriptionRepository) GetActiveByChatID(ctx context.Context, chatID int64) ([]*entity.Subscription, error) {
 	var subscriptions []*entity.Subscription
 	err := r.db.WithContext(ctx).
@@ -68,42 +84,225 @@ func (r *GormSubscriptionRepository) GetActiveByChatID(ctx context.Context, chat
 	return subscriptions, err
 }
 
+func (r *GormSubscriptionRepository) List(ctx context.Context, filter SubscriptionFilter, offset, limit int) ([]*entity.Subscription, int64, error) {
+	var total int64
+	if err := r.filtered(ctx, filter).Model(&entity.Subscription{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var subscriptions []*entity.Subscription
+	err := r.filtered(ctx, filter).
+		Preload("NotificationType").
+		Order("id").
+		Offset(offset).
+		Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, total, err
+}
+
+// filtered starts a subscription query limited to the filter's matches
+func (r *GormSubscriptionRepository) filtered(ctx context.Context, filter SubscriptionFilter) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.ChatID != nil {
+		query = query.Where("chat_id = ?", *filter.ChatID)
+	}
+	if filter.NotificationTypeID != nil {
+		query = query.Where("notification_type_id = ?", *filter.NotificationTypeID)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	return query
+}
+
 func (r *GormSubscriptionRepository) GetActiveByType(ctx context.Context, notificationTypeID int) ([]*entity.Subscription, error) {
 	var subscriptions []*entity.Subscription
 	err := r.db.WithContext(ctx).
 		Preload("User").
 		Preload("NotificationType").
 		Where("notification_type_id = ? AND is_active = ?", notificationTypeID, true).
+		Scopes(approvedOwners).
 		Find(&subscriptions).Error
 	return subscriptions, err
 }
 
+// approvedOwners limits a query to subscriptions owned by approved users, so disabling
+// a user pauses their subscriptions and enabling them again resumes them
+func approvedOwners(db *gorm.DB) *gorm.DB {
+	return db.Where("user_id IN (SELECT id FROM users WHERE approval_status = ?)", "approved")
+}
+
 func (r *GormSubscriptionRepository) GetDueForNotification(ctx context.Context, notificationTypeID int) ([]*entity.Subscription, error) {
+	var candidates []*entity.Subscription
+
+	if err := dueCandidates(r.db.WithContext(ctx), notificationTypeID).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	return filterDue(candidates, time.Now()), nil
+}
+
+func (r *GormSubscriptionRepository) ClaimDueForNotification(ctx context.Context, notificationTypeID int) ([]*entity.Subscription, error) {
 	var subscriptions []*entity.Subscription
 
+	// SKIP LOCKED keeps concurrent dispatchers from seeing the rows this one is claiming;
+	// advancing last_notified_at before commit keeps them from seeing the rows afterwards
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var candidates []*entity.Subscription
+		err := dueCandidates(tx, notificationTypeID).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}, Options: "SKIP LOCKED"}).
+			Find(&candidates).Error
+		if err != nil {
+			return err
+		}
+
+		subscriptions = filterDue(candidates, time.Now())
+		if len(subscriptions) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(subscriptions))
+		for i, subscription := range subscriptions {
+			ids[i] = subscription.ID
+		}
+
+		return tx.Model(&entity.Subscription{}).
+			Where("id IN ?", ids).
+			Update("last_notified_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// dueCandidates narrows down in SQL to active subscriptions of approved users that haven't
+// been notified yet, are due based on interval, or follow a cron schedule; filterDue makes
+// the final decision. Subscriptions of pending, rejected or disabled users are never due.
+func dueCandidates(db *gorm.DB, notificationTypeID int) *gorm.DB {
 	// Subquery to get the interval from preferences or default from notification type
-	query := r.db.WithContext(ctx).
+	return db.
 		Preload("User").
 		Preload("NotificationType").
-		Where("notification_type_id = ? AND is_active = ?", notificationTypeID, true)
-
-	// Get subscriptions that haven't been notified yet or are due based on interval
-	query = query.Where(`
-		last_notified_at IS NULL OR 
-		last_notified_at <= NOW() - INTERVAL '1 minute' * COALESCE(
-			CAST(preferences->>'interval' AS INTEGER), 
-			(SELECT default_interval_minutes FROM notification_types WHERE id = subscriptions.notification_type_id)
-		)
-	`)
-
-	err := query.Find(&subscriptions).Error
-	return subscriptions, err
+		Where("notification_type_id = ? AND is_active = ?", notificationTypeID, true).
+		Scopes(approvedOwners).
+		Where(`
+			preferences ? 'schedule' OR
+			last_notified_at IS NULL OR 
+			last_notified_at <= NOW() - INTERVAL '1 minute' * COALESCE(
+				CAST(preferences->>'interval' AS INTEGER), 
+				(SELECT default_interval_minutes FROM notification_types WHERE id = subscriptions.notification_type_id)
+			)
+		`)
+}
+
+// filterDue keeps the candidates whose schedule and quiet hours say they are due at now
+func filterDue(candidates []*entity.Subscription, now time.Time) []*entity.Subscription {
+	subscriptions := make([]*entity.Subscription, 0, len(candidates))
+	for _, subscription := range candidates {
+		due, err := isDue(subscription, now)
+		if err != nil {
+			slog.Warn("Skipping subscription with invalid schedule", "subscriptionID", subscription.ID, "error", err)
+			continue
+		}
+		if due {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions
+}
+
+// isDue evaluates a subscription's interval, cron schedule and quiet hours in the user's time zone
+func isDue(subscription *entity.Subscription, now time.Time) (bool, error) {
+	loc, err := schedule.LoadLocation(subscription.User.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	intervalMinutes := subscription.Preferences.Interval
+	if intervalMinutes == 0 {
+		intervalMinutes = subscription.NotificationType.DefaultIntervalMinutes
+	}
+
+	rules := schedule.Rules{
+		Interval:   time.Duration(intervalMinutes) * time.Minute,
+		Cron:       subscription.Preferences.Schedule,
+		QuietHours: subscription.Preferences.QuietHours,
+		Location:   loc,
+	}
+	return rules.IsDue(now, subscription.LastNotifiedAt, subscription.CreatedAt)
+}
+
+func (r *GormSubscriptionRepository) CountActiveByType(ctx context.Context) (map[int]int64, error) {
+	var rows []struct {
+		NotificationTypeID int
+		Count              int64
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&entity.Subscription{}).
+		Select("notification_type_id, COUNT(*) AS count").
+		Where("is_active = ?", true).
+		Group("notification_type_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.NotificationTypeID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *GormSubscriptionRepository) GetMinIntervals(ctx context.Context) (map[int]int, error) {
+	var rows []struct {
+		NotificationTypeID int
+		MinInterval        int
+	}
+
+	// Same effective interval as GetDueForNotification: the preference if set, else the type
+	// default. Cron schedules can fire on any minute, so they need a one-minute tick.
+	err := r.db.WithContext(ctx).
+		Model(&entity.Subscription{}).
+		Select(`subscriptions.notification_type_id, MIN(CASE
+			WHEN subscriptions.preferences ? 'schedule' THEN 1
+			ELSE COALESCE(
+				NULLIF(CAST(subscriptions.preferences->>'interval' AS INTEGER), 0),
+				notification_types.default_interval_minutes
+			)
+		END) AS min_interval`).
+		Joins("JOIN notification_types ON notification_types.id = subscriptions.notification_type_id").
+		Where("subscriptions.is_active = ?", true).
+		Group("subscriptions.notification_type_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[int]int, len(rows))
+	for _, row := range rows {
+		intervals[row.NotificationTypeID] = row.MinInterval
+	}
+	return intervals, nil
 }
 
 func (r *GormSubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) error {
 	return r.db.WithContext(ctx).Save(subscription).Error
 }
 
+func (r *GormSubscriptionRepository) SetLastNotified(ctx context.Context, id int64, at *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Subscription{}).
+		Where("id = ?", id).
+		Update("last_notified_at", at).Error
+}
+
 func (r *GormSubscriptionRepository) UpdateLastNotified(ctx context.Context, id int64) error {
 	now := time.Now()
 	return r.db.WithContext(ctx).
@@ -121,3 +320,9 @@ func (r *GormSubscriptionRepository) DeleteByUserAndType(ctx context.Context, us
 		Where("user_id = ? AND notification_type_id = ?", userID, notificationTypeID).
 		Delete(&entity.Subscription{}).Error
 }
+
+func (r *GormSubscriptionRepository) DeleteByChatAndType(ctx context.Context, chatID int64, notificationTypeID int) error {
+	return r.db.WithContext(ctx).
+		Where("chat_id = ? AND notification_type_id = ?", chatID, notificationTypeID).
+		Delete(&entity.Subscription{}).Error
+}
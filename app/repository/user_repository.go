@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go-messaging/entity"
@@ -69,6 +70,35 @@ func (r *GormUserRepository) GetUsersByApprovalStatusWithLimit(ctx context.Conte
 	return users, err
 }
 
+// GetUsersByApprovalStatusPage orders by ID after creation time so users created in
+// the same instant keep their place between pages
+func (r *GormUserRepository) GetUsersByApprovalStatusPage(ctx context.Context, status string, offset, limit int) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.WithContext(ctx).
+		Where("approval_status = ?", status).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *GormUserRepository) SearchByName(ctx context.Context, name string, limit int) ([]entity.User, error) {
+	// Match the name literally, not as a LIKE pattern
+	pattern := "%" + likeEscaper.Replace(name) + "%"
+
+	var users []entity.User
+	err := r.db.WithContext(ctx).
+		Where("username ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ?", pattern, pattern, pattern).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// likeEscaper escapes the LIKE wildcards, using Postgres' default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r *GormUserRepository) CountUsersByApprovalStatus(ctx context.Context, status string) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&entity.User{}).Where("approval_status = ?", status).Count(&count).Error
@@ -81,6 +111,12 @@ func (r *GormUserRepository) CountUsersByRole(ctx context.Context, role string)
 	return count, err
 }
 
+func (r *GormUserRepository) GetUsersByRoleAndApprovalStatus(ctx context.Context, role, status string) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.WithContext(ctx).Where("role = ? AND approval_status = ?", role, status).Order("created_at").Find(&users).Error
+	return users, err
+}
+
 func (r *GormUserRepository) CountAll(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&entity.User{}).Count(&count).Error
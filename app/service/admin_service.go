@@ -2,22 +2,30 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-messaging/entity"
 	"go-messaging/repository"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AdminService struct {
-	userRepo repository.UserRepository
+	userRepo                 repository.UserRepository
+	approvalNotificationRepo repository.ApprovalNotificationRepository
 }
 
 type AdminServiceInterface interface {
 	GetPendingUsers(ctx context.Context) ([]entity.User, error)
 	GetApprovedUsers(ctx context.Context, limit int) ([]entity.User, error)
+	GetUsersPage(ctx context.Context, status string, offset, limit int) ([]entity.User, int64, error)
+	FindUsers(ctx context.Context, query string, limit int) ([]entity.User, error)
+	GetAdmins(ctx context.Context) ([]entity.User, error)
 	ApproveUser(ctx context.Context, userID uuid.UUID, adminID uuid.UUID) error
 	RejectUser(ctx context.Context, userID uuid.UUID, adminID uuid.UUID) error
 	DisableUser(ctx context.Context, userID uuid.UUID, adminID uuid.UUID) error
@@ -26,11 +34,16 @@ type AdminServiceInterface interface {
 	IsAdmin(ctx context.Context, telegramUserID int64) (bool, error)
 	GetUserStats(ctx context.Context) (map[string]int64, error)
 	CleanupPendingUsers(ctx context.Context) (int, error)
+	ClaimApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64) (bool, error)
+	RecordApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64, messageID int) error
+	ReleaseApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64) error
+	TakeApprovalNotifications(ctx context.Context, userID uuid.UUID) ([]*entity.ApprovalNotification, error)
 }
 
-func NewAdminService(userRepo repository.UserRepository) AdminServiceInterface {
+func NewAdminService(userRepo repository.UserRepository, approvalNotificationRepo repository.ApprovalNotificationRepository) AdminServiceInterface {
 	return &AdminService{
-		userRepo: userRepo,
+		userRepo:                 userRepo,
+		approvalNotificationRepo: approvalNotificationRepo,
 	}
 }
 
@@ -52,6 +65,68 @@ func (s *AdminService) GetApprovedUsers(ctx context.Context, limit int) ([]entit
 	return users, nil
 }
 
+// GetUsersPage returns one page of the users with a status, newest first, and how many
+// users have that status in total
+func (s *AdminService) GetUsersPage(ctx context.Context, status string, offset, limit int) ([]entity.User, int64, error) {
+	total, err := s.userRepo.CountUsersByApprovalStatus(ctx, status)
+	if err != nil {
+		slog.Error("Failed to count users", "status", status, "error", err)
+		return nil, 0, err
+	}
+
+	users, err := s.userRepo.GetUsersByApprovalStatusPage(ctx, status, offset, limit)
+	if err != nil {
+		slog.Error("Failed to get users page", "status", status, "offset", offset, "error", err)
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// FindUsers looks users up by internal ID, Telegram ID or @username. Anything else is
+// matched against usernames and names, returning at most limit users.
+func (s *AdminService) FindUsers(ctx context.Context, query string, limit int) ([]entity.User, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+
+	var (
+		user *entity.User
+		err  error
+	)
+	if id, parseErr := uuid.Parse(query); parseErr == nil {
+		user, err = s.userRepo.GetByID(ctx, id)
+	} else if telegramUserID, parseErr := strconv.ParseInt(query, 10, 64); parseErr == nil {
+		user, err = s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
+	} else {
+		users, err := s.userRepo.SearchByName(ctx, strings.TrimPrefix(query, "@"), limit)
+		if err != nil {
+			slog.Error("Failed to search users", "query", query, "error", err)
+			return nil, err
+		}
+		return users, nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("Failed to find user", "query", query, "error", err)
+		return nil, err
+	}
+	return []entity.User{*user}, nil
+}
+
+// GetAdmins returns the approved admins
+func (s *AdminService) GetAdmins(ctx context.Context) ([]entity.User, error) {
+	admins, err := s.userRepo.GetUsersByRoleAndApprovalStatus(ctx, "admin", "approved")
+	if err != nil {
+		slog.Error("Failed to get admins", "error", err)
+		return nil, err
+	}
+	return admins, nil
+}
+
 func (s *AdminService) ApproveUser(ctx context.Context, userID uuid.UUID, adminID uuid.UUID) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -100,6 +175,8 @@ func (s *AdminService) RejectUser(ctx context.Context, userID uuid.UUID, adminID
 	return nil
 }
 
+// DisableUser disables a user. Their subscriptions are kept but paused, since only
+// subscriptions of approved users are ever due; EnableUser resumes them.
 func (s *AdminService) DisableUser(ctx context.Context, userID uuid.UUID, adminID uuid.UUID) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -252,3 +329,43 @@ func (s *AdminService) CleanupPendingUsers(ctx context.Context) (int, error) {
 
 	return count, nil
 }
+
+// ClaimApprovalNotification reserves the right to tell an admin about a pending user.
+// It returns false when that admin has already been told.
+func (s *AdminService) ClaimApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64) (bool, error) {
+	claimed, err := s.approvalNotificationRepo.Claim(ctx, userID, adminChatID)
+	if err != nil {
+		slog.Error("Failed to claim approval notification", "userID", userID, "adminChatID", adminChatID, "error", err)
+		return false, err
+	}
+	return claimed, nil
+}
+
+// RecordApprovalNotification stores the message an admin was sent about a pending user
+func (s *AdminService) RecordApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64, messageID int) error {
+	err := s.approvalNotificationRepo.SetMessageID(ctx, userID, adminChatID, messageID)
+	if err != nil {
+		slog.Error("Failed to record approval notification", "userID", userID, "adminChatID", adminChatID, "error", err)
+	}
+	return err
+}
+
+// ReleaseApprovalNotification drops a claim whose message could not be sent
+func (s *AdminService) ReleaseApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64) error {
+	err := s.approvalNotificationRepo.Release(ctx, userID, adminChatID)
+	if err != nil {
+		slog.Error("Failed to release approval notification", "userID", userID, "adminChatID", adminChatID, "error", err)
+	}
+	return err
+}
+
+// TakeApprovalNotifications removes and returns the messages admins were sent about a
+// user, so whoever acts on the user updates each message exactly once
+func (s *AdminService) TakeApprovalNotifications(ctx context.Context, userID uuid.UUID) ([]*entity.ApprovalNotification, error) {
+	notifications, err := s.approvalNotificationRepo.TakeByUserID(ctx, userID)
+	if err != nil {
+		slog.Error("Failed to take approval notifications", "userID", userID, "error", err)
+		return nil, err
+	}
+	return notifications, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-messaging/model"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidBroadcastMessage is returned for an empty or oversized broadcast message
+var ErrInvalidBroadcastMessage = errors.New("invalid broadcast message")
+
+// broadcastJobRetention is how long finished jobs can still be polled
+const broadcastJobRetention = 24 * time.Hour
+
+// BroadcastServiceImpl implements BroadcastService. Jobs live in memory, so they can be
+// polled on the instance that started them until broadcastJobRetention after they finish.
+type BroadcastServiceImpl struct {
+	notificationTypeService NotificationTypeService
+	subscriptionService     SubscriptionService
+	dispatchService         NotificationDispatchService
+
+	mutex sync.Mutex
+	jobs  map[string]*BroadcastJob
+}
+
+// NewBroadcastService creates a new broadcast service
+func NewBroadcastService(
+	notificationTypeService NotificationTypeService,
+	subscriptionService SubscriptionService,
+	dispatchService NotificationDispatchService,
+) BroadcastService {
+	return &BroadcastServiceImpl{
+		notificationTypeService: notificationTypeService,
+		subscriptionService:     subscriptionService,
+		dispatchService:         dispatchService,
+		jobs:                    make(map[string]*BroadcastJob),
+	}
+}
+
+func (s *BroadcastServiceImpl) Broadcast(ctx context.Context, notificationTypeCode, message string, dryRun bool) (*BroadcastJob, error) {
+	if err := model.ValidateMessageString(message); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBroadcastMessage, err)
+	}
+
+	if _, err := s.notificationTypeService.GetTypeByCode(ctx, notificationTypeCode); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.subscriptionService.GetActiveSubscriptions(ctx, notificationTypeCode)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &BroadcastJob{
+		ID:               uuid.New().String(),
+		NotificationType: notificationTypeCode,
+		Status:           BroadcastRunning,
+		DryRun:           dryRun,
+		Total:            len(subscriptions),
+		StartedAt:        time.Now(),
+	}
+	if dryRun {
+		job.Status = BroadcastDryRun
+		job.FinishedAt = &job.StartedAt
+	}
+
+	s.mutex.Lock()
+	s.removeFinished(time.Now())
+	s.jobs[job.ID] = job
+	copied := *job
+	s.mutex.Unlock()
+
+	if dryRun {
+		return &copied, nil
+	}
+
+	slog.Info("Starting broadcast", "jobID", job.ID, "notificationType", notificationTypeCode, "recipients", job.Total)
+
+	// The broadcast outlives the request that started it
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		for _, subscription := range subscriptions {
+			err := s.dispatchService.DispatchToSubscription(ctx, subscription, message)
+
+			s.mutex.Lock()
+			if err != nil {
+				job.Failed++
+			} else {
+				job.Sent++
+			}
+			s.mutex.Unlock()
+
+			if err != nil {
+				slog.Warn("Failed to broadcast to subscription", "jobID", job.ID, "subscriptionID", subscription.ID, "error", err)
+			}
+		}
+
+		s.mutex.Lock()
+		now := time.Now()
+		job.Status = BroadcastCompleted
+		job.FinishedAt = &now
+		s.mutex.Unlock()
+
+		slog.Info("Finished broadcast", "jobID", job.ID, "sent", job.Sent, "failed", job.Failed)
+	}()
+
+	return &copied, nil
+}
+
+func (s *BroadcastServiceImpl) GetJob(id string) (*BroadcastJob, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *job
+	return &copied, true
+}
+
+// removeFinished forgets jobs that finished too long ago; the caller must hold the mutex
+func (s *BroadcastServiceImpl) removeFinished(now time.Time) {
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > broadcastJobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
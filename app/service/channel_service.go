@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-messaging/entity"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrChannelNotFound is returned when Telegram does not know the channel or it is not a channel
+	ErrChannelNotFound = errors.New("channel not found")
+	// ErrChannelCannotPost is returned when the bot is not an admin allowed to post in the channel
+	ErrChannelCannotPost = errors.New("bot cannot post in channel")
+	// ErrNotAdmin is returned when a non-admin tries to manage channel subscriptions
+	ErrNotAdmin = errors.New("admin permissions required")
+)
+
+// ChannelInfo describes a Telegram channel the bot can post to
+type ChannelInfo struct {
+	ChatID   int64  `json:"chat_id"`
+	Title    string `json:"title"`
+	Username string `json:"username,omitempty"`
+}
+
+// ChannelResolver looks up channels through the Telegram Bot API
+type ChannelResolver interface {
+	// ResolveChannel finds a channel by numeric ID or @username and checks that the
+	// bot may post in it
+	ResolveChannel(ctx context.Context, channel string) (*ChannelInfo, error)
+}
+
+// ChannelService manages subscriptions that post into Telegram channels
+type ChannelService interface {
+	// SubscribeChannel subscribes a channel to a notification type on behalf of an admin
+	SubscribeChannel(ctx context.Context, adminID uuid.UUID, channel, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (*entity.Subscription, *ChannelInfo, error)
+
+	// UnsubscribeChannel removes a channel's subscription to a notification type
+	UnsubscribeChannel(ctx context.Context, adminID uuid.UUID, channel, notificationTypeCode string) error
+
+	// GetChannelSubscriptions retrieves the active subscriptions of a channel
+	GetChannelSubscriptions(ctx context.Context, channel string) ([]*entity.Subscription, *ChannelInfo, error)
+}
+
+// ChannelServiceImpl implements ChannelService
+type ChannelServiceImpl struct {
+	resolver            ChannelResolver
+	userService         UserService
+	subscriptionService SubscriptionService
+}
+
+// NewChannelService creates a new channel service
+func NewChannelService(resolver ChannelResolver, userService UserService, subscriptionService SubscriptionService) ChannelService {
+	return &ChannelServiceImpl{
+		resolver:            resolver,
+		userService:         userService,
+		subscriptionService: subscriptionService,
+	}
+}
+
+func (s *ChannelServiceImpl) SubscribeChannel(ctx context.Context, adminID uuid.UUID, channel, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (*entity.Subscription, *ChannelInfo, error) {
+	admin, err := s.getAdmin(ctx, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := s.resolver.ResolveChannel(ctx, channel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The channel becomes the subscription's chat; the admin owns it
+	subscription, err := s.subscriptionService.Subscribe(ctx, admin.TelegramUserID, info.ChatID, notificationTypeCode, preferences)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subscription, info, nil
+}
+
+func (s *ChannelServiceImpl) UnsubscribeChannel(ctx context.Context, adminID uuid.UUID, channel, notificationTypeCode string) error {
+	admin, err := s.getAdmin(ctx, adminID)
+	if err != nil {
+		return err
+	}
+
+	// A numeric ID needs no lookup, so channels the bot was removed from can still be cleaned up
+	chatID, err := strconv.ParseInt(channel, 10, 64)
+	if err != nil {
+		info, err := s.resolver.ResolveChannel(ctx, channel)
+		if err != nil {
+			return err
+		}
+		chatID = info.ChatID
+	}
+
+	return s.subscriptionService.Unsubscribe(ctx, admin.TelegramUserID, chatID, notificationTypeCode)
+}
+
+func (s *ChannelServiceImpl) GetChannelSubscriptions(ctx context.Context, channel string) ([]*entity.Subscription, *ChannelInfo, error) {
+	info, err := s.resolver.ResolveChannel(ctx, channel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscriptions, err := s.subscriptionService.GetChatSubscriptions(ctx, info.ChatID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subscriptions, info, nil
+}
+
+// getAdmin returns the user if they are an approved admin
+func (s *ChannelServiceImpl) getAdmin(ctx context.Context, adminID uuid.UUID) (*entity.User, error) {
+	admin, err := s.userService.GetUserByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.Role != "admin" || admin.ApprovalStatus != "approved" {
+		return nil, ErrNotAdmin
+	}
+	return admin, nil
+}
+
+// ParseChannelID converts a channel reference as typed by an admin into a Bot API chat ID:
+// a numeric ID such as -1001234567890, or a public @username
+func ParseChannelID(channel string) (interface{}, error) {
+	channel = strings.TrimSpace(channel)
+	if id, err := strconv.ParseInt(channel, 10, 64); err == nil {
+		return id, nil
+	}
+
+	if strings.HasPrefix(channel, "@") && len(channel) > 1 {
+		return channel, nil
+	}
+
+	// t.me links are a common way to share a channel
+	for _, prefix := range []string{"https://t.me/", "http://t.me/", "t.me/"} {
+		if name, ok := strings.CutPrefix(channel, prefix); ok && name != "" {
+			return "@" + name, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: expected a numeric ID or @username, got %q", ErrChannelNotFound, channel)
+}
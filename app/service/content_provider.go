@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go-messaging/entity"
+)
+
+// ContentProvider generates notification content for a single notification type
+type ContentProvider interface {
+	// Code returns the notification type code this provider serves
+	Code() string
+
+	// GetContent generates the message for a subscription's preferences
+	GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error)
+}
+
+// SubscriptionContentProvider is implemented by providers that need the whole
+// subscription, for example to keep per-subscription state between runs
+type SubscriptionContentProvider interface {
+	ContentProvider
+
+	// GetSubscriptionContent generates the message for a specific subscription
+	GetSubscriptionContent(ctx context.Context, subscription *entity.Subscription) (string, error)
+}
+
+// DeliveryAwareProvider is implemented by providers whose state must only advance
+// once their content has been handed off for delivery, so a failed send is retried
+// instead of lost
+type DeliveryAwareProvider interface {
+	// Delivered commits the state behind content that was queued or sent
+	Delivered(ctx context.Context, subscription *entity.Subscription) error
+
+	// Undelivered discards the state behind content that could not be sent
+	Undelivered(subscription *entity.Subscription)
+}
+
+// SkipPolicy controls how LastNotifiedAt is treated when a provider skips a send
+type SkipPolicy int
+
+const (
+	// SkipAdvance marks the subscription as notified, so the next check waits a full interval
+	SkipAdvance SkipPolicy = iota
+	// SkipHold leaves LastNotifiedAt untouched, so the subscription is checked again on the next run
+	SkipHold
+)
+
+// SkipPolicyProvider is implemented by providers that need a non-default skip policy
+type SkipPolicyProvider interface {
+	SkipPolicy() SkipPolicy
+}
+
+// SkipError is returned by a provider that has nothing to report for a subscription.
+// It is not a failure: the send is skipped and logged with the 'skipped' status.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return "notification skipped: " + e.Reason
+}
+
+// Skip returns an error that tells the dispatcher to skip this send
+func Skip(reason string) error {
+	return &SkipError{Reason: reason}
+}
+
+// AsSkip reports whether err is a skip result and returns it
+func AsSkip(err error) (*SkipError, bool) {
+	var skipErr *SkipError
+	if errors.As(err, &skipErr) {
+		return skipErr, true
+	}
+	return nil, false
+}
+
+// ContentProviderRegistry maps notification type codes to content providers. Types
+// whose notifications are sent by other services, such as detections or broadcasts,
+// are registered as external instead.
+type ContentProviderRegistry struct {
+	providers map[string]ContentProvider
+	external  map[string]bool
+	mutex     sync.RWMutex
+}
+
+// NewContentProviderRegistry creates an empty content provider registry
+func NewContentProviderRegistry() *ContentProviderRegistry {
+	return &ContentProviderRegistry{
+		providers: make(map[string]ContentProvider),
+		external:  make(map[string]bool),
+	}
+}
+
+// Register adds a provider to the registry, rejecting duplicate codes
+func (r *ContentProviderRegistry) Register(provider ContentProvider) error {
+	if provider == nil || provider.Code() == "" {
+		return fmt.Errorf("content provider must have a non-empty code")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.providers[provider.Code()]; exists {
+		return fmt.Errorf("content provider for '%s' is already registered", provider.Code())
+	}
+	if r.external[provider.Code()] {
+		return fmt.Errorf("'%s' is already registered as externally delivered", provider.Code())
+	}
+
+	r.providers[provider.Code()] = provider
+	return nil
+}
+
+// RegisterExternal marks notification types as delivered outside the dispatcher.
+// They can be subscribed to, but are never scheduled.
+func (r *ContentProviderRegistry) RegisterExternal(codes ...string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, code := range codes {
+		if code == "" {
+			return fmt.Errorf("externally delivered type must have a non-empty code")
+		}
+		if _, exists := r.providers[code]; exists {
+			return fmt.Errorf("content provider for '%s' is already registered", code)
+		}
+		r.external[code] = true
+	}
+	return nil
+}
+
+// IsExternal reports whether a notification type is delivered outside the dispatcher
+func (r *ContentProviderRegistry) IsExternal(code string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.external[code]
+}
+
+// Delivers reports whether subscribers of a notification type can receive anything,
+// from a provider or from the service that delivers it
+func (r *ContentProviderRegistry) Delivers(code string) bool {
+	return r.Has(code) || r.IsExternal(code)
+}
+
+// Get returns the provider registered for a notification type code
+func (r *ContentProviderRegistry) Get(code string) (ContentProvider, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	provider, ok := r.providers[code]
+	return provider, ok
+}
+
+// SkipPolicy returns the skip policy for a notification type code
+func (r *ContentProviderRegistry) SkipPolicy(code string) SkipPolicy {
+	provider, ok := r.Get(code)
+	if !ok {
+		return SkipAdvance
+	}
+	if policyProvider, ok := provider.(SkipPolicyProvider); ok {
+		return policyProvider.SkipPolicy()
+	}
+	return SkipAdvance
+}
+
+// Has reports whether a provider is registered for a notification type code
+func (r *ContentProviderRegistry) Has(code string) bool {
+	_, ok := r.Get(code)
+	return ok
+}
+
+// Codes returns all registered notification type codes in sorted order
+func (r *ContentProviderRegistry) Codes() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	codes := make([]string, 0, len(r.providers))
+	for code := range r.providers {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Unregistered returns the codes of the given types that have no provider and are
+// not delivered externally
+func (r *ContentProviderRegistry) Unregistered(types []*entity.NotificationType) []string {
+	var missing []string
+	for _, nt := range types {
+		if !r.Delivers(nt.Code) {
+			missing = append(missing, nt.Code)
+		}
+	}
+	return missing
+}
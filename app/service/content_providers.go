@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/internal/price"
+	"go-messaging/repository"
+)
+
+// NewDefaultContentProviderRegistry creates a registry with the built-in providers
+func NewDefaultContentProviderRegistry(prices price.Client, alertStates repository.PriceAlertStateRepository) *ContentProviderRegistry {
+	registry := NewContentProviderRegistry()
+
+	for _, provider := range []ContentProvider{
+		&CoinbaseContentProvider{prices: prices},
+		&NewsContentProvider{},
+		&WeatherContentProvider{},
+		NewPriceAlertContentProvider(prices, alertStates),
+		&CustomContentProvider{},
+	} {
+		// Built-in codes are unique, so registration cannot fail
+		_ = registry.Register(provider)
+	}
+
+	// Detections and admin broadcasts are sent by their own services
+	_ = registry.RegisterExternal(DetectionNotificationType, "maintenance", "system")
+
+	return registry
+}
+
+// CoinbaseContentProvider generates cryptocurrency price updates
+type CoinbaseContentProvider struct {
+	prices price.Client
+}
+
+func (p *CoinbaseContentProvider) Code() string { return "coinbase" }
+
+func (p *CoinbaseContentProvider) GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
+	currency := "BTC"
+	if preferences != nil && preferences.Currency != "" {
+		currency = strings.ToUpper(preferences.Currency)
+	}
+	quote := quoteCurrency(preferences)
+
+	currentPrice, err := p.prices.GetSpotPrice(ctx, currency, quote)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s price: %w", currency, err)
+	}
+
+	return fmt.Sprintf("🪙 %s Price Update\n\nCurrent price: %s\n\nUpdated: %s",
+		currency, formatPrice(currentPrice, quote), time.Now().Format("15:04 MST")), nil
+}
+
+// NewsContentProvider generates news digests filtered by keywords
+type NewsContentProvider struct{}
+
+func (p *NewsContentProvider) Code() string { return "news" }
+
+// SkipPolicy holds LastNotifiedAt so matching news is sent as soon as it appears
+func (p *NewsContentProvider) SkipPolicy() SkipPolicy { return SkipHold }
+
+func (p *NewsContentProvider) GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
+	keywords := []string{"technology", "crypto"}
+	filtered := false
+	if preferences != nil && len(preferences.Keywords) > 0 {
+		keywords = preferences.Keywords
+		filtered = true
+	}
+
+	// Mock news content - replace with actual news API integration
+	news := fetchNews(keywords)
+
+	// Subscribers with their own keywords only want matching articles
+	if filtered && len(news) == 0 {
+		return "", Skip(fmt.Sprintf("no news matching %s", strings.Join(keywords, ", ")))
+	}
+	if len(news) == 0 {
+		news = defaultNews()
+	}
+
+	var content strings.Builder
+	content.WriteString("📰 Latest News\n\n")
+
+	for i, article := range news {
+		if i >= 3 { // Limit to 3 articles
+			break
+		}
+		content.WriteString(fmt.Sprintf("• %s\n", article))
+	}
+
+	content.WriteString(fmt.Sprintf("\nUpdated: %s", time.Now().Format("15:04 MST")))
+
+	return content.String(), nil
+}
+
+// WeatherContentProvider generates weather updates for a location
+type WeatherContentProvider struct{}
+
+func (p *WeatherContentProvider) Code() string { return "weather" }
+
+func (p *WeatherContentProvider) GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
+	location := "San Francisco, CA"
+	if preferences != nil && preferences.Settings != nil {
+		if loc, ok := preferences.Settings["location"]; ok {
+			location = loc
+		}
+	}
+
+	// Mock weather data - replace with actual weather API integration
+	weather := fetchWeather(location)
+
+	return fmt.Sprintf("🌤 Weather Update for %s\n\n%s\n\nUpdated: %s",
+		location, weather, time.Now().Format("15:04 MST")), nil
+}
+
+// CustomContentProvider sends the message stored in subscription settings
+type CustomContentProvider struct{}
+
+func (p *CustomContentProvider) Code() string { return "custom" }
+
+func (p *CustomContentProvider) GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
+	customMessage := "Custom notification"
+	if preferences != nil && preferences.Settings != nil {
+		if msg, ok := preferences.Settings["message"]; ok {
+			customMessage = msg
+		}
+	}
+
+	return fmt.Sprintf("🔔 Custom Notification\n\n%s\n\nSent: %s",
+		customMessage, time.Now().Format("15:04 MST")), nil
+}
+
+// quoteCurrency returns the currency prices are quoted in for a subscription
+func quoteCurrency(preferences *entity.SubscriptionPreferences) string {
+	if preferences != nil && preferences.Settings != nil {
+		if quote, ok := preferences.Settings["quote"]; ok && quote != "" {
+			return strings.ToUpper(quote)
+		}
+	}
+	return price.DefaultQuoteCurrency
+}
+
+// formatPrice renders an amount with a dollar sign for USD and a suffix otherwise
+func formatPrice(amount float64, quote string) string {
+	if quote == "USD" {
+		return fmt.Sprintf("$%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, quote)
+}
+
+// Mock external API calls - replace with actual implementations
+
+// mockArticles stands in for a news API feed
+var mockArticles = []string{
+	"Bitcoin reaches new all-time high amid institutional adoption",
+	"Major tech companies announce blockchain partnerships",
+	"Cryptocurrency regulation updates from global markets",
+	"New DeFi protocol launches with innovative features",
+	"Market analysis: Crypto winter may be ending",
+}
+
+func fetchNews(keywords []string) []string {
+	// Mock implementation - replace with actual news API call
+	articles := mockArticles
+
+	// Filter by keywords (simplified)
+	var filtered []string
+	for _, article := range articles {
+		for _, keyword := range keywords {
+			if strings.Contains(strings.ToLower(article), strings.ToLower(keyword)) {
+				filtered = append(filtered, article)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+func defaultNews() []string {
+	return mockArticles[:3] // Return first 3 if no matches
+}
+
+func fetchWeather(location string) string {
+	// Mock implementation - replace with actual weather API call
+	weathers := []string{
+		"Sunny, 72°F (22°C)\nWind: 5 mph\nHumidity: 45%",
+		"Partly cloudy, 68°F (20°C)\nWind: 8 mph\nHumidity: 55%",
+		"Light rain, 65°F (18°C)\nWind: 12 mph\nHumidity: 78%",
+	}
+
+	// Return based on location hash (simplified)
+	index := len(location) % len(weathers)
+	return weathers[index]
+}
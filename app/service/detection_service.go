@@ -2,23 +2,145 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"go-messaging/entity"
 	"go-messaging/model"
 )
 
+// DetectionNotificationType is the notification type detections are delivered to
+const DetectionNotificationType = "security"
+
+// ErrInvalidDetection is returned for a detection summary that cannot be routed
+var ErrInvalidDetection = errors.New("invalid detection")
+
+// Risk levels, lowest first. Each subscription's min_risk setting picks the lowest
+// level it is sent; without one only high and critical detections are delivered,
+// so on-call chats are not paged for routine findings.
+var riskLevels = []string{"low", "medium", "high", "critical"}
+
+const defaultMinRisk = "high"
+
+var riskIcons = map[string]string{
+	"low":      "🟢",
+	"medium":   "🟡",
+	"high":     "🟠",
+	"critical": "🔴",
+}
+
 type DetectionService struct {
-	// Add necessary fields here, e.g., repositories, loggers, etc.
+	subscriptionService SubscriptionService
+	dispatchService     NotificationDispatchService
 }
 
 // NewDetectionService creates a new instance of DetectionService
-func NewDetectionService() DetectionInterface {
-	return &DetectionService{}
+func NewDetectionService(subscriptionService SubscriptionService, dispatchService NotificationDispatchService) DetectionInterface {
+	return &DetectionService{
+		subscriptionService: subscriptionService,
+		dispatchService:     dispatchService,
+	}
+}
+
+func (s *DetectionService) SendDetectionNotification(ctx context.Context, request model.DetectionSummary) (*DispatchResult, error) {
+	risk := strings.ToLower(strings.TrimSpace(request.RiskLevel))
+	if riskRank(risk) < 0 {
+		return nil, fmt.Errorf("%w: risk level %q is not one of %s", ErrInvalidDetection, request.RiskLevel, strings.Join(riskLevels, ", "))
+	}
+
+	subscriptions, err := s.subscriptionService.GetActiveSubscriptions(ctx, DetectionNotificationType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s subscriptions: %w", DetectionNotificationType, err)
+	}
+
+	message := FormatDetection(request)
+	result := &DispatchResult{NotificationType: DetectionNotificationType, Total: len(subscriptions)}
+
+	for _, subscription := range subscriptions {
+		if riskRank(risk) < riskRank(minRisk(subscription)) {
+			result.Skipped++
+			continue
+		}
+
+		if err := s.dispatchService.DispatchToSubscription(ctx, subscription, message); err != nil {
+			result.Failed++
+			slog.Warn("Failed to deliver detection", "subscriptionID", subscription.ID, "risk", risk, "error", err)
+			continue
+		}
+		result.Sent++
+	}
+
+	slog.Info("Delivered detection", "filename", request.Filename, "risk", risk,
+		"sent", result.Sent, "skipped", result.Skipped, "failed", result.Failed)
+
+	return result, nil
 }
 
-// Implement methods for DetectionService here
-func (s *DetectionService) SendDetectionNotification(ctx context.Context, request model.DetectionSummary) error {
-	// Implement the logic to send detection notification
+// FormatDetection renders a detection summary as a Telegram message
+func FormatDetection(request model.DetectionSummary) string {
+	risk := strings.ToLower(strings.TrimSpace(request.RiskLevel))
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "%s %s risk detection\n", riskIcons[risk], strings.ToUpper(risk))
+	if request.Filename != "" {
+		fmt.Fprintf(&message, "\n📄 File: %s", request.Filename)
+	}
+	if request.Classification != "" {
+		fmt.Fprintf(&message, "\n🏷️ Classification: %s", request.Classification)
+	}
+	if request.Confidence != "" {
+		fmt.Fprintf(&message, "\n🎯 Confidence: %s", request.Confidence)
+	}
+	if request.Summary != "" {
+		fmt.Fprintf(&message, "\n\n%s", request.Summary)
+	}
+	if len(request.KeyFindings) > 0 {
+		message.WriteString("\n\n🔍 Key findings:")
+		for _, finding := range request.KeyFindings {
+			fmt.Fprintf(&message, "\n• %s", finding)
+		}
+	}
+	if request.ActionRequired != "" {
+		fmt.Fprintf(&message, "\n\n⚠️ Action required: %s", request.ActionRequired)
+	}
+	if request.ProcessingTime != "" {
+		fmt.Fprintf(&message, "\n\n⏱️ Processed in %s", request.ProcessingTime)
+	}
 
-	// Send to all ?
+	return truncateMessage(message.String(), model.MAX_MESSAGE_LENGTH)
+}
+
+// truncateMessage cuts a message to at most limit bytes without splitting a character
+func truncateMessage(message string, limit int) string {
+	if len(message) <= limit {
+		return message
+	}
+
+	const ellipsis = "…"
+	cut := limit - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + ellipsis
+}
+
+// riskRank returns a risk level's position in riskLevels, or -1 if it is unknown
+func riskRank(risk string) int {
+	for i, level := range riskLevels {
+		if level == risk {
+			return i
+		}
+	}
+	return -1
+}
 
-	return nil
+// minRisk returns the lowest risk level a subscription wants to hear about
+func minRisk(subscription *entity.Subscription) string {
+	if risk := strings.ToLower(subscription.Preferences.Settings["min_risk"]); riskRank(risk) >= 0 {
+		return risk
+	}
+	return defaultMinRisk
 }
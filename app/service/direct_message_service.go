@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+)
+
+// ErrInvalidDirectMessage is returned for a direct message Telegram would not accept
+var ErrInvalidDirectMessage = errors.New("invalid direct message")
+
+// maxCallbackDataBytes is Telegram's limit on a button's callback data
+const maxCallbackDataBytes = 64
+
+// maxDirectMessageButtons is Telegram's limit on buttons under one message
+const maxDirectMessageButtons = 100
+
+var directMessageParseModes = map[string]bool{
+	"":           true,
+	"MarkdownV2": true,
+	"HTML":       true,
+	"Markdown":   true,
+}
+
+// DirectMessageServiceImpl implements DirectMessageService
+type DirectMessageServiceImpl struct {
+	userService UserService
+	outbox      OutboxService
+}
+
+// NewDirectMessageService creates a new direct message service. Messages go through
+// the outbox, so they share its rate limit, retries and notification logging.
+func NewDirectMessageService(userService UserService, outbox OutboxService) DirectMessageService {
+	return &DirectMessageServiceImpl{
+		userService: userService,
+		outbox:      outbox,
+	}
+}
+
+func (s *DirectMessageServiceImpl) Send(ctx context.Context, message DirectMessage) (*entity.OutboundMessage, error) {
+	if err := validateDirectMessage(message); err != nil {
+		return nil, err
+	}
+
+	chatID, err := s.resolveChat(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.outbox.EnqueueChatMessage(ctx, chatID, message.Text, message.ParseMode, message.Buttons)
+}
+
+func (s *DirectMessageServiceImpl) GetMessage(ctx context.Context, id int64) (*entity.OutboundMessage, error) {
+	return s.outbox.GetMessage(ctx, id)
+}
+
+// resolveChat returns the chat a message goes to. Users are messaged in their private
+// chat with the bot, whose ID is their Telegram user ID, and only once approved.
+func (s *DirectMessageServiceImpl) resolveChat(ctx context.Context, message DirectMessage) (int64, error) {
+	if message.ChatID != nil {
+		return *message.ChatID, nil
+	}
+
+	var user *entity.User
+	var err error
+	if message.TelegramUserID != nil {
+		user, err = s.userService.GetUserByTelegramID(ctx, *message.TelegramUserID)
+	} else {
+		user, err = s.userService.GetUserByID(ctx, *message.UserID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Pending, rejected and disabled users are not messaged
+	if user.ApprovalStatus != "approved" {
+		return 0, &UserNotApprovedError{Status: user.ApprovalStatus}
+	}
+	return user.TelegramUserID, nil
+}
+
+// validateDirectMessage checks what Telegram would otherwise reject only once the
+// message is sent
+func validateDirectMessage(message DirectMessage) error {
+	targets := 0
+	if message.TelegramUserID != nil {
+		targets++
+	}
+	if message.UserID != nil {
+		targets++
+	}
+	if message.ChatID != nil {
+		targets++
+	}
+	if targets != 1 {
+		return fmt.Errorf("%w: set exactly one of telegram_user_id, user_id and chat_id", ErrInvalidDirectMessage)
+	}
+
+	if err := model.ValidateMessageString(message.Text); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDirectMessage, err)
+	}
+
+	if !directMessageParseModes[message.ParseMode] {
+		return fmt.Errorf("%w: parse mode %q is not MarkdownV2, HTML or Markdown", ErrInvalidDirectMessage, message.ParseMode)
+	}
+
+	count := 0
+	for _, row := range message.Buttons {
+		for _, button := range row {
+			count++
+			if err := validateButton(button); err != nil {
+				return fmt.Errorf("%w: button %q %v", ErrInvalidDirectMessage, button.Text, err)
+			}
+		}
+	}
+	if count > maxDirectMessageButtons {
+		return fmt.Errorf("%w: %d buttons (max %d)", ErrInvalidDirectMessage, count, maxDirectMessageButtons)
+	}
+
+	return nil
+}
+
+func validateButton(button entity.InlineButton) error {
+	if button.Text == "" {
+		return fmt.Errorf("has no text")
+	}
+	if (button.URL == "") == (button.CallbackData == "") {
+		return fmt.Errorf("needs exactly one of url and callback_data")
+	}
+	if len(button.CallbackData) > maxCallbackDataBytes {
+		return fmt.Errorf("callback data is longer than %d bytes", maxCallbackDataBytes)
+	}
+	if button.URL != "" {
+		parsed, err := url.Parse(button.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "tg") {
+			return fmt.Errorf("url must be http, https or tg")
+		}
+	}
+	return nil
+}
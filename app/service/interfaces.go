@@ -4,32 +4,80 @@ import (
 	"context"
 	"go-messaging/entity"
 	"go-messaging/model"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // SubscriptionService defines the interface for subscription business logic
 type SubscriptionService interface {
-	// Subscribe creates or updates a subscription for a user
+	// Subscribe creates or updates the subscription of a chat, owned by the subscribing user.
+	// A chat has at most one subscription per notification type; for a private chat the
+	// chat ID is the user's own Telegram ID. Subscribe, Unsubscribe and UpdatePreferences
+	// return ErrChatAccessDenied unless the user may manage the chat.
 	Subscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (*entity.Subscription, error)
 
-	// Unsubscribe removes a subscription for a user
-	Unsubscribe(ctx context.Context, telegramUserID int64, notificationTypeCode string) error
+	// SetChatAdminChecker sets how group and channel admins are recognised when a user
+	// manages the subscriptions of a chat other than their private chat
+	SetChatAdminChecker(checker ChatAdminChecker)
 
-	// GetUserSubscriptions retrieves all subscriptions for a user
+	// Unsubscribe removes the subscription of a chat
+	Unsubscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string) error
+
+	// GetUserSubscriptions retrieves all subscriptions owned by a user, in any chat
 	GetUserSubscriptions(ctx context.Context, telegramUserID int64) ([]*entity.Subscription, error)
 
+	// GetChatSubscriptions retrieves the active subscriptions of a chat
+	GetChatSubscriptions(ctx context.Context, chatID int64) ([]*entity.Subscription, error)
+
 	// GetActiveSubscriptions retrieves all active subscriptions for a notification type
 	GetActiveSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error)
 
 	// GetDueSubscriptions retrieves subscriptions that are due for notification
 	GetDueSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error)
 
-	// UpdatePreferences updates subscription preferences
-	UpdatePreferences(ctx context.Context, telegramUserID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) error
+	// GetMinIntervals returns the shortest interval in minutes any active subscriber needs, keyed by notification type ID
+	GetMinIntervals(ctx context.Context) (map[int]int, error)
+
+	// UpdatePreferences updates the preferences of a chat's subscription
+	UpdatePreferences(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) error
 
 	// MarkNotified updates the last notified timestamp for a subscription
 	MarkNotified(ctx context.Context, subscriptionID int64) error
+
+	// ClaimDueSubscriptions claims due subscriptions for this dispatcher and marks them notified.
+	// Claims that end up not being sent must be handed back with ReleaseClaim.
+	ClaimDueSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error)
+
+	// ReleaseClaim restores a claimed subscription's previous last notified timestamp
+	ReleaseClaim(ctx context.Context, subscription *entity.Subscription) error
+
+	// ListSubscriptions retrieves one page of the subscriptions matching a filter and how
+	// many match in total
+	ListSubscriptions(ctx context.Context, filter SubscriptionFilter, offset, limit int) ([]*entity.Subscription, int64, error)
+
+	// GetSubscription retrieves a subscription by ID
+	GetSubscription(ctx context.Context, id int64) (*entity.Subscription, error)
+
+	// UpdateSubscriptionPreferences replaces a subscription's preferences, validated
+	// against its notification type's preference schema
+	UpdateSubscriptionPreferences(ctx context.Context, id int64, preferences *entity.SubscriptionPreferences) (*entity.Subscription, error)
+
+	// SetSubscriptionActive pauses or resumes a subscription. Paused subscriptions keep
+	// their preferences but are never due.
+	SetSubscriptionActive(ctx context.Context, id int64, active bool) (*entity.Subscription, error)
+
+	// DeleteSubscription removes a subscription by ID
+	DeleteSubscription(ctx context.Context, id int64) error
+}
+
+// SubscriptionFilter narrows ListSubscriptions; zero fields match everything
+type SubscriptionFilter struct {
+	UserID               *uuid.UUID
+	TelegramUserID       *int64 // owner's Telegram ID, an alternative to UserID
+	ChatID               *int64
+	NotificationTypeCode string
+	IsActive             *bool
 }
 
 // UserService defines the interface for user business logic
@@ -54,6 +102,17 @@ type UserService interface {
 
 	// CountUsers returns the total number of users
 	CountUsers(ctx context.Context) (int64, error)
+
+	// UpdateTimezone sets the IANA time zone used for a user's schedules and quiet hours
+	UpdateTimezone(ctx context.Context, telegramUserID int64, timezone string) error
+
+	// SetRegistrationNotifier sets who is told about new users waiting for approval
+	SetRegistrationNotifier(notifier RegistrationNotifier)
+}
+
+// RegistrationNotifier is told when a new user registers and waits for approval
+type RegistrationNotifier interface {
+	NotifyPendingUser(ctx context.Context, user *entity.User)
 }
 
 // NotificationTypeService defines the interface for notification type business logic
@@ -70,10 +129,10 @@ type NotificationTypeService interface {
 	// GetTypeByID retrieves a notification type by ID
 	GetTypeByID(ctx context.Context, id int) (*entity.NotificationType, error)
 
-	// CreateType creates a new notification type
-	CreateType(ctx context.Context, code, name string, description *string, defaultInterval int) (*entity.NotificationType, error)
+	// CreateType creates a new notification type with its preference schema, which must pass ValidatePreferenceSchema
+	CreateType(ctx context.Context, code, name string, description *string, defaultInterval int, schema entity.PreferenceSchema) (*entity.NotificationType, error)
 
-	// UpdateType updates a notification type
+	// UpdateType updates a notification type, validating its preference schema
 	UpdateType(ctx context.Context, notificationType *entity.NotificationType) error
 
 	// ActivateType activates a notification type
@@ -81,6 +140,22 @@ type NotificationTypeService interface {
 
 	// DeactivateType deactivates a notification type
 	DeactivateType(ctx context.Context, code string) error
+
+	// DeleteType deletes a notification type along with its subscriptions. Types with
+	// active subscriptions are only deleted when force is set.
+	DeleteType(ctx context.Context, code string, force bool) error
+
+	// GetSubscriberCounts returns the number of active subscriptions, keyed by notification type ID
+	GetSubscriberCounts(ctx context.Context) (map[int]int64, error)
+
+	// SetChangeListener sets who is told when a notification type is created or changed
+	SetChangeListener(listener NotificationTypeChangeListener)
+}
+
+// NotificationTypeChangeListener is told when notification types change, so schedules
+// built from them can be rebuilt
+type NotificationTypeChangeListener interface {
+	TriggerReload()
 }
 
 // NotificationLogService defines the interface for notification log business logic
@@ -88,6 +163,9 @@ type NotificationLogService interface {
 	// LogNotification creates a notification log entry
 	LogNotification(ctx context.Context, subscriptionID int64, message, status string, errorMessage *string) (*entity.NotificationLog, error)
 
+	// LogChatMessage creates a log entry for a direct message sent outside any subscription
+	LogChatMessage(ctx context.Context, chatID int64, message, status string, errorMessage *string) (*entity.NotificationLog, error)
+
 	// GetSubscriptionLogs retrieves logs for a subscription with pagination
 	GetSubscriptionLogs(ctx context.Context, subscriptionID int64, offset, limit int) ([]*entity.NotificationLog, error)
 
@@ -104,7 +182,11 @@ type NotificationLogService interface {
 // NotificationDispatchService defines the interface for sending notifications
 type NotificationDispatchService interface {
 	// DispatchNotification sends a notification for a specific type
-	DispatchNotification(ctx context.Context, notificationTypeCode string) error
+	DispatchNotification(ctx context.Context, notificationTypeCode string) (*DispatchResult, error)
+
+	// Dispatches reports whether a type has a content provider, so it needs scheduling.
+	// Externally delivered types such as security do not.
+	Dispatches(notificationTypeCode string) bool
 
 	// DispatchToSubscription sends a notification to a specific subscription
 	DispatchToSubscription(ctx context.Context, subscription *entity.Subscription, message string) error
@@ -113,6 +195,94 @@ type NotificationDispatchService interface {
 	GetNotificationContent(ctx context.Context, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (string, error)
 }
 
+// OutboxService defines the interface for the durable outbound message queue
+type OutboxService interface {
+	// Enqueue stores a message for delivery by the sender loop
+	Enqueue(ctx context.Context, subscriptionID *int64, chatID int64, message string) (*entity.OutboundMessage, error)
+
+	// EnqueueChatMessage stores a direct message with a parse mode and optional buttons
+	EnqueueChatMessage(ctx context.Context, chatID int64, message, parseMode string, buttons entity.InlineButtons) (*entity.OutboundMessage, error)
+
+	// GetMessage retrieves a queued message and its delivery status
+	GetMessage(ctx context.Context, id int64) (*entity.OutboundMessage, error)
+
+	// ProcessDue sends up to batchSize due messages and returns how many were attempted
+	ProcessDue(ctx context.Context, batchSize int) (int, error)
+
+	// RecoverStale returns messages left in sending by a crashed process to the queue
+	RecoverStale(ctx context.Context) (int, error)
+
+	// GetDeadLetters retrieves messages that exhausted their retries
+	GetDeadLetters(ctx context.Context, offset, limit int) ([]*entity.OutboundMessage, int64, error)
+
+	// Requeue moves a dead-lettered message back to the queue with a fresh attempt budget
+	Requeue(ctx context.Context, id int64) error
+}
+
+// DispatchResult summarises a single dispatch run for a notification type
+type DispatchResult struct {
+	NotificationType string        `json:"notification_type"`
+	Total            int           `json:"total"`
+	Sent             int           `json:"sent"`
+	Failed           int           `json:"failed"`
+	Skipped          int           `json:"skipped"`
+	Cancelled        int           `json:"cancelled"`
+	Duration         time.Duration `json:"duration"`
+}
+
+// DetectionInterface delivers detection results to security subscribers
 type DetectionInterface interface {
-	SendDetectionNotification(ctx context.Context, request model.DetectionSummary) error
+	// SendDetectionNotification formats a detection and sends it to every security
+	// subscription whose min_risk it meets
+	SendDetectionNotification(ctx context.Context, request model.DetectionSummary) (*DispatchResult, error)
+}
+
+// Broadcast job statuses
+const (
+	BroadcastRunning   = "running"
+	BroadcastCompleted = "completed"
+	BroadcastDryRun    = "dry_run"
+)
+
+// BroadcastJob tracks one broadcast to the subscribers of a notification type
+type BroadcastJob struct {
+	ID               string     `json:"id"`
+	NotificationType string     `json:"notification_type"`
+	Status           string     `json:"status"`
+	DryRun           bool       `json:"dry_run"`
+	Total            int        `json:"total"` // recipients
+	Sent             int        `json:"sent"`  // handed to the outbox, which retries and logs delivery
+	Failed           int        `json:"failed"`
+	StartedAt        time.Time  `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+}
+
+// BroadcastService sends one-off messages to every subscriber of a notification type
+type BroadcastService interface {
+	// Broadcast starts sending message to every active subscription of a notification
+	// type and returns the job tracking it. A dry run only counts the recipients.
+	Broadcast(ctx context.Context, notificationTypeCode, message string, dryRun bool) (*BroadcastJob, error)
+
+	// GetJob returns the progress of a broadcast started in this process
+	GetJob(id string) (*BroadcastJob, bool)
+}
+
+// DirectMessage is a one-off message to a single user or chat. Exactly one of
+// TelegramUserID, UserID and ChatID is set.
+type DirectMessage struct {
+	TelegramUserID *int64
+	UserID         *uuid.UUID
+	ChatID         *int64
+	Text           string
+	ParseMode      string // empty for plain text, MarkdownV2, HTML or Markdown
+	Buttons        entity.InlineButtons
+}
+
+// DirectMessageService delivers messages from other services through the outbox
+type DirectMessageService interface {
+	// Send validates the message, resolves its target chat and queues it for delivery
+	Send(ctx context.Context, message DirectMessage) (*entity.OutboundMessage, error)
+
+	// GetMessage retrieves a queued message and its delivery status
+	GetMessage(ctx context.Context, id int64) (*entity.OutboundMessage, error)
 }
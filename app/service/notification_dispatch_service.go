@@ -3,10 +3,11 @@ package service
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sync"
 	"time"
 
 	"go-messaging/entity"
+	"go-messaging/internal/price"
 	"go-messaging/model"
 )
 
@@ -15,56 +16,174 @@ type NotificationDispatchServiceImpl struct {
 	subscriptionService SubscriptionService
 	logService          NotificationLogService
 	telegramService     TelegramNotificationSender
+	contentProviders    *ContentProviderRegistry
+	outbox              OutboxService
+	config              DispatchConfig
+
+	// skipped remembers the reason each subscription was last skipped for, so a subscription
+	// skipped run after run logs one skipped row per streak instead of one per run
+	skippedMutex sync.Mutex
+	skipped      map[int64]string
+}
+
+// DispatchConfig controls how many notifications are sent concurrently
+type DispatchConfig struct {
+	// DefaultConcurrency is the number of workers per notification type
+	DefaultConcurrency int
+	// TypeConcurrency overrides the worker count for specific notification type codes
+	TypeConcurrency map[string]int
+}
+
+// DefaultDispatchConfig returns the dispatch settings used when none are configured
+func DefaultDispatchConfig() DispatchConfig {
+	return DispatchConfig{
+		DefaultConcurrency: 4,
+	}
+}
+
+// concurrencyFor returns the worker count for a notification type
+func (c DispatchConfig) concurrencyFor(notificationTypeCode string) int {
+	if n, ok := c.TypeConcurrency[notificationTypeCode]; ok && n > 0 {
+		return n
+	}
+	if c.DefaultConcurrency > 0 {
+		return c.DefaultConcurrency
+	}
+	return 1
 }
 
 // TelegramNotificationSender defines interface for sending Telegram messages
 type TelegramNotificationSender interface {
 	SendMessage(chatID int64, message string) error
 	SendMessageWithKeyboard(chatID int64, message string, keyboard model.InlineKeyboardMarkup) error
+	// SendMessageWithKeyboardID is SendMessageWithKeyboard returning the new message's ID
+	SendMessageWithKeyboardID(chatID int64, message string, keyboard model.InlineKeyboardMarkup) (int, error)
+	// SendFormattedMessage sends a message in a parse mode with optional buttons and returns its message ID
+	SendFormattedMessage(chatID int64, message, parseMode string, keyboard *model.InlineKeyboardMarkup) (int, error)
+	// EditMessageWithKeyboard replaces a message's text and buttons; a nil keyboard removes the buttons
+	EditMessageWithKeyboard(chatID int64, messageID int, message string, keyboard *model.InlineKeyboardMarkup) error
+	// EditMessageKeyboard replaces only a message's buttons
+	EditMessageKeyboard(chatID int64, messageID int, keyboard *model.InlineKeyboardMarkup) error
+	DeleteMessage(chatID int64, messageID int) error
 	AnswerCallbackQuery(callbackID, text string) error
 }
 
-// NewNotificationDispatchService creates a new notification dispatch service
+// NewNotificationDispatchService creates a new notification dispatch service.
+// When outbox is non-nil messages are queued for the outbox sender instead of sent directly.
 func NewNotificationDispatchService(
 	subscriptionService SubscriptionService,
 	logService NotificationLogService,
 	telegramService TelegramNotificationSender,
+	contentProviders *ContentProviderRegistry,
+	outbox OutboxService,
+	config DispatchConfig,
 ) NotificationDispatchService {
 	return &NotificationDispatchServiceImpl{
 		subscriptionService: subscriptionService,
 		logService:          logService,
 		telegramService:     telegramService,
+		contentProviders:    contentProviders,
+		outbox:              outbox,
+		config:              config,
+		skipped:             make(map[int64]string),
 	}
 }
 
-func (s *NotificationDispatchServiceImpl) DispatchNotification(ctx context.Context, notificationTypeCode string) error {
-	// Get subscriptions that are due for notification
-	subscriptions, err := s.subscriptionService.GetDueSubscriptions(ctx, notificationTypeCode)
+func (s *NotificationDispatchServiceImpl) Dispatches(notificationTypeCode string) bool {
+	return s.contentProviders.Has(notificationTypeCode)
+}
+
+func (s *NotificationDispatchServiceImpl) DispatchNotification(ctx context.Context, notificationTypeCode string) (*DispatchResult, error) {
+	result := &DispatchResult{NotificationType: notificationTypeCode}
+	startedAt := time.Now()
+	defer func() { result.Duration = time.Since(startedAt) }()
+
+	// Types without a provider, such as security, are sent by their own services
+	if !s.Dispatches(notificationTypeCode) {
+		return result, nil
+	}
+
+	// Share fetched prices across all subscriptions in this run
+	ctx = price.WithRunCache(ctx)
+
+	// Claim subscriptions that are due for notification, so other instances skip them
+	subscriptions, err := s.subscriptionService.ClaimDueSubscriptions(ctx, notificationTypeCode)
 	if err != nil {
-		return fmt.Errorf("failed to get due subscriptions: %w", err)
+		return result, fmt.Errorf("failed to claim due subscriptions: %w", err)
 	}
 
-	fmt.Printf("📋 Found %d due subscriptions for %s\n", len(subscriptions), notificationTypeCode)
+	fmt.Printf("📋 Claimed %d due subscriptions for %s\n", len(subscriptions), notificationTypeCode)
 
+	result.Total = len(subscriptions)
 	if len(subscriptions) == 0 {
-		return nil // No subscriptions to notify
+		return result, nil // No subscriptions to notify
 	}
 
-	// Send notifications to all due subscriptions
-	successCount := 0
+	workers := s.config.concurrencyFor(notificationTypeCode)
+	if workers > len(subscriptions) {
+		workers = len(subscriptions)
+	}
+
+	jobs := make(chan *entity.Subscription)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	// Send notifications to all due subscriptions using a bounded worker pool
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for subscription := range jobs {
+				fmt.Printf("📤 Processing subscription %d for user %s\n", subscription.ID, subscription.UserID)
+				outcome, err := s.processSubscriptionNotification(ctx, subscription, notificationTypeCode)
+
+				mutex.Lock()
+				switch {
+				case err != nil:
+					// Log error but continue with other subscriptions
+					result.Failed++
+					fmt.Printf("Failed to process notification for subscription %d: %v\n", subscription.ID, err)
+				case outcome == outcomeSkipped:
+					result.Skipped++
+					fmt.Printf("⏭️ Skipped subscription %d\n", subscription.ID)
+				default:
+					result.Sent++
+					fmt.Printf("✅ Successfully processed subscription %d\n", subscription.ID)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	// Stop handing out work once the context is cancelled; in-flight sends finish
+	fed := 0
+feed:
 	for _, subscription := range subscriptions {
-		fmt.Printf("📤 Processing subscription %d for user %d\n", subscription.ID, subscription.UserID)
-		if err := s.processSubscriptionNotification(ctx, subscription, notificationTypeCode); err != nil {
-			// Log error but continue with other subscriptions
-			fmt.Printf("Failed to process notification for subscription %d: %v\n", subscription.ID, err)
-		} else {
-			successCount++
-			fmt.Printf("✅ Successfully processed subscription %d\n", subscription.ID)
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- subscription:
+			fed++
 		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	fmt.Printf("📊 Processed %d/%d subscriptions successfully for %s\n", successCount, len(subscriptions), notificationTypeCode)
-	return nil
+	// Hand back claims we never got to so the next run picks them up
+	for _, subscription := range subscriptions[fed:] {
+		s.releaseClaim(ctx, subscription)
+	}
+
+	result.Cancelled = result.Total - result.Sent - result.Failed - result.Skipped
+
+	fmt.Printf("📊 Processed %d/%d subscriptions successfully for %s (%d skipped, %d failed, %d cancelled) in %s\n",
+		result.Sent, result.Total, notificationTypeCode, result.Skipped, result.Failed, result.Cancelled,
+		time.Since(startedAt).Round(time.Millisecond))
+
+	if result.Cancelled > 0 {
+		return result, fmt.Errorf("dispatch of %s cancelled: %w", notificationTypeCode, ctx.Err())
+	}
+	return result, nil
 }
 
 func (s *NotificationDispatchServiceImpl) DispatchToSubscription(ctx context.Context, subscription *entity.Subscription, message string) error {
@@ -72,252 +191,169 @@ func (s *NotificationDispatchServiceImpl) DispatchToSubscription(ctx context.Con
 }
 
 func (s *NotificationDispatchServiceImpl) GetNotificationContent(ctx context.Context, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (string, error) {
-	switch notificationTypeCode {
-	case "coinbase":
-		return s.getCoinbaseContent(ctx, preferences)
-	case "news":
-		return s.getNewsContent(ctx, preferences)
-	case "weather":
-		return s.getWeatherContent(ctx, preferences)
-	case "price_alert":
-		return s.getPriceAlertContent(ctx, preferences)
-	case "custom":
-		return s.getCustomContent(ctx, preferences)
-	default:
-		return "", fmt.Errorf("unknown notification type: %s", notificationTypeCode)
+	provider, ok := s.contentProviders.Get(notificationTypeCode)
+	if !ok {
+		return "", fmt.Errorf("no content provider registered for notification type: %s", notificationTypeCode)
 	}
-}
 
-func (s *NotificationDispatchServiceImpl) processSubscriptionNotification(ctx context.Context, subscription *entity.Subscription, notificationTypeCode string) error {
-	fmt.Printf("🔄 Generating content for %s notification (subscription %d)\n", notificationTypeCode, subscription.ID)
+	return provider.GetContent(ctx, preferences)
+}
 
-	// Generate notification content
-	content, err := s.GetNotificationContent(ctx, notificationTypeCode, &subscription.Preferences)
-	if err != nil {
-		return fmt.Errorf("failed to get notification content: %w", err)
+// getSubscriptionContent generates content, passing the whole subscription to providers that need it
+func (s *NotificationDispatchServiceImpl) getSubscriptionContent(ctx context.Context, notificationTypeCode string, subscription *entity.Subscription) (string, error) {
+	provider, ok := s.contentProviders.Get(notificationTypeCode)
+	if !ok {
+		return "", fmt.Errorf("no content provider registered for notification type: %s", notificationTypeCode)
 	}
 
-	fmt.Printf("📝 Generated content for subscription %d: %.100s...\n", subscription.ID, content)
-
-	// Send the notification
-	if err := s.sendNotificationToSubscription(ctx, subscription, content); err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+	if subscriptionProvider, ok := provider.(SubscriptionContentProvider); ok {
+		return subscriptionProvider.GetSubscriptionContent(ctx, subscription)
 	}
 
-	fmt.Printf("📨 Sent notification for subscription %d\n", subscription.ID)
+	return provider.GetContent(ctx, &subscription.Preferences)
+}
 
-	// Mark subscription as notified
-	if err := s.subscriptionService.MarkNotified(ctx, subscription.ID); err != nil {
-		return fmt.Errorf("failed to mark subscription as notified: %w", err)
-	}
+// dispatchOutcome describes what happened to a single subscription in a dispatch run
+type dispatchOutcome int
 
-	fmt.Printf("✅ Marked subscription %d as notified\n", subscription.ID)
-	return nil
-}
+const (
+	outcomeSent dispatchOutcome = iota
+	outcomeSkipped
+	outcomeFailed
+)
 
-func (s *NotificationDispatchServiceImpl) sendNotificationToSubscription(ctx context.Context, subscription *entity.Subscription, message string) error {
-	// Validate message length
-	if err := model.ValidateMessageString(message); err != nil {
-		errorMsg := err.Error()
-		_, logErr := s.logService.LogNotification(ctx, subscription.ID, message, "failed", &errorMsg)
-		if logErr != nil {
-			fmt.Printf("Failed to log notification error: %v\n", logErr)
-		}
-		return err
-	}
+func (s *NotificationDispatchServiceImpl) processSubscriptionNotification(ctx context.Context, subscription *entity.Subscription, notificationTypeCode string) (dispatchOutcome, error) {
+	fmt.Printf("🔄 Generating content for %s notification (subscription %d)\n", notificationTypeCode, subscription.ID)
 
-	// Send via Telegram
-	if err := s.telegramService.SendMessage(subscription.ChatID, message); err != nil {
-		errorMsg := err.Error()
-		_, logErr := s.logService.LogNotification(ctx, subscription.ID, message, "failed", &errorMsg)
-		if logErr != nil {
-			fmt.Printf("Failed to log notification error: %v\n", logErr)
-		}
-		return fmt.Errorf("failed to send telegram message: %w", err)
+	// Generate notification content
+	content, err := s.getSubscriptionContent(ctx, notificationTypeCode, subscription)
+	if skip, ok := AsSkip(err); ok {
+		return s.skipSubscriptionNotification(ctx, subscription, notificationTypeCode, skip)
 	}
-
-	// Log successful notification
-	_, err := s.logService.LogNotification(ctx, subscription.ID, message, "sent", nil)
+	s.endSkipStreak(subscription)
 	if err != nil {
-		fmt.Printf("Failed to log notification success: %v\n", err)
-		// Don't return error as the notification was sent successfully
+		s.releaseClaim(ctx, subscription)
+		return outcomeFailed, fmt.Errorf("failed to get notification content: %w", err)
 	}
 
-	return nil
-}
-
-// Content generation methods for different notification types
-
-func (s *NotificationDispatchServiceImpl) getCoinbaseContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
-	currency := "BTC"
-	if preferences != nil && preferences.Currency != "" {
-		currency = strings.ToUpper(preferences.Currency)
-	}
+	fmt.Printf("📝 Generated content for subscription %d: %.100s...\n", subscription.ID, content)
 
-	// Mock API call - replace with actual Coinbase API integration
-	price, err := s.fetchCoinbasePrice(currency)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch %s price: %w", currency, err)
+	// Send the notification; the claim already marked the subscription as notified
+	if err := s.sendNotificationToSubscription(ctx, subscription, content); err != nil {
+		s.notifyDelivery(ctx, notificationTypeCode, subscription, err)
+		s.releaseClaim(ctx, subscription)
+		return outcomeFailed, fmt.Errorf("failed to send notification: %w", err)
 	}
+	s.notifyDelivery(ctx, notificationTypeCode, subscription, nil)
 
-	return fmt.Sprintf("🪙 %s Price Update\n\nCurrent price: $%.2f\n\nUpdated: %s",
-		currency, price, time.Now().Format("15:04 MST")), nil
+	fmt.Printf("📨 Sent notification for subscription %d\n", subscription.ID)
+	return outcomeSent, nil
 }
 
-func (s *NotificationDispatchServiceImpl) getNewsContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
-	keywords := []string{"technology", "crypto"}
-	if preferences != nil && len(preferences.Keywords) > 0 {
-		keywords = preferences.Keywords
+// notifyDelivery tells a delivery-aware provider whether its content was handed off
+func (s *NotificationDispatchServiceImpl) notifyDelivery(ctx context.Context, notificationTypeCode string, subscription *entity.Subscription, sendErr error) {
+	provider, ok := s.contentProviders.Get(notificationTypeCode)
+	if !ok {
+		return
 	}
-
-	// Mock news content - replace with actual news API integration
-	news := s.fetchNews(keywords)
-
-	var content strings.Builder
-	content.WriteString("📰 Latest News\n\n")
-
-	for i, article := range news {
-		if i >= 3 { // Limit to 3 articles
-			break
-		}
-		content.WriteString(fmt.Sprintf("• %s\n", article))
+	deliveryAware, ok := provider.(DeliveryAwareProvider)
+	if !ok {
+		return
 	}
 
-	content.WriteString(fmt.Sprintf("\nUpdated: %s", time.Now().Format("15:04 MST")))
-
-	return content.String(), nil
+	if sendErr != nil {
+		deliveryAware.Undelivered(subscription)
+		return
+	}
+	// The message is queued, so commit even if the run is being cancelled
+	if err := deliveryAware.Delivered(context.WithoutCancel(ctx), subscription); err != nil {
+		fmt.Printf("Failed to commit delivered state for subscription %d: %v\n", subscription.ID, err)
+	}
 }
 
-func (s *NotificationDispatchServiceImpl) getWeatherContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
-	location := "San Francisco, CA"
-	if preferences != nil && preferences.Settings != nil {
-		if loc, ok := preferences.Settings["location"]; ok {
-			location = loc
-		}
+// releaseClaim undoes a claim so the subscription is retried on the next run
+func (s *NotificationDispatchServiceImpl) releaseClaim(ctx context.Context, subscription *entity.Subscription) {
+	// Release even when the run was cancelled, otherwise the subscription waits a full interval
+	if err := s.subscriptionService.ReleaseClaim(context.WithoutCancel(ctx), subscription); err != nil {
+		fmt.Printf("Failed to release claim on subscription %d: %v\n", subscription.ID, err)
 	}
-
-	// Mock weather data - replace with actual weather API integration
-	weather := s.fetchWeather(location)
-
-	return fmt.Sprintf("🌤 Weather Update for %s\n\n%s\n\nUpdated: %s",
-		location, weather, time.Now().Format("15:04 MST")), nil
 }
 
-func (s *NotificationDispatchServiceImpl) getPriceAlertContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
-	// Provide default values if preferences are missing or incomplete
-	currency := "BTC"
-	threshold := 50000.0
+// skipSubscriptionNotification logs a skipped send and applies the type's skip policy
+func (s *NotificationDispatchServiceImpl) skipSubscriptionNotification(ctx context.Context, subscription *entity.Subscription, notificationTypeCode string, skip *SkipError) (dispatchOutcome, error) {
+	fmt.Printf("⏭️ Skipping subscription %d: %s\n", subscription.ID, skip.Reason)
 
-	if preferences != nil {
-		if preferences.Currency != "" {
-			currency = strings.ToUpper(preferences.Currency)
-		}
-		if preferences.Threshold > 0 {
-			threshold = preferences.Threshold
+	if s.startSkipStreak(subscription, skip.Reason) {
+		if _, err := s.logService.LogNotification(ctx, subscription.ID, skip.Reason, "skipped", nil); err != nil {
+			fmt.Printf("Failed to log skipped notification: %v\n", err)
 		}
 	}
 
-	// Mock price check - replace with actual API integration
-	currentPrice, err := s.fetchCoinbasePrice(currency)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch %s price: %w", currency, err)
+	// The claim advanced LastNotifiedAt; SkipHold puts it back to re-check on the next run
+	if s.contentProviders.SkipPolicy(notificationTypeCode) == SkipHold {
+		s.releaseClaim(ctx, subscription)
 	}
 
-	// For prototype/dev: Always send notification regardless of threshold
-	// In production, you'd uncomment the condition below:
-	/*
-		if currentPrice >= threshold {
-			return fmt.Sprintf("🚨 Price Alert: %s\n\nCurrent price: $%.2f\nThreshold: $%.2f\n\nAlert triggered at %s",
-				currency, currentPrice, threshold, time.Now().Format("15:04 MST")), nil
-		}
-		return "", fmt.Errorf("price threshold not met")
-	*/
-
-	// Development version: Always send notification with current price info
-	status := "📊" // Default status
-	if currentPrice >= threshold {
-		status = "🚨" // Alert status if threshold would be met
-	}
-
-	return fmt.Sprintf("%s Price Alert: %s\n\nCurrent price: $%.2f\nThreshold: $%.2f\nStatus: %s\n\nUpdate time: %s",
-		status, currency, currentPrice, threshold,
-		func() string {
-			if currentPrice >= threshold {
-				return "THRESHOLD MET"
-			}
-			return "Monitoring"
-		}(),
-		time.Now().Format("15:04 MST")), nil
+	return outcomeSkipped, nil
 }
 
-func (s *NotificationDispatchServiceImpl) getCustomContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
-	customMessage := "Custom notification"
-	if preferences != nil && preferences.Settings != nil {
-		if msg, ok := preferences.Settings["message"]; ok {
-			customMessage = msg
-		}
-	}
+// startSkipStreak records a skip and reports whether it starts a new streak,
+// that is the subscription was not already being skipped for the same reason
+func (s *NotificationDispatchServiceImpl) startSkipStreak(subscription *entity.Subscription, reason string) bool {
+	s.skippedMutex.Lock()
+	defer s.skippedMutex.Unlock()
 
-	return fmt.Sprintf("🔔 Custom Notification\n\n%s\n\nSent: %s",
-		customMessage, time.Now().Format("15:04 MST")), nil
-}
-
-// Mock external API calls - replace with actual implementations
-
-func (s *NotificationDispatchServiceImpl) fetchCoinbasePrice(currency string) (float64, error) {
-	// Mock implementation - replace with actual Coinbase API call
-	prices := map[string]float64{
-		"BTC": 45000.50,
-		"ETH": 3200.75,
-		"ADA": 1.25,
-		"DOT": 35.80,
+	if last, ok := s.skipped[subscription.ID]; ok && last == reason {
+		return false
 	}
+	s.skipped[subscription.ID] = reason
+	return true
+}
 
-	if price, ok := prices[currency]; ok {
-		// Add some randomness to simulate price changes
-		return price + (float64(time.Now().Unix()%100) - 50), nil
-	}
+// endSkipStreak forgets a skip streak once the provider produces content again
+func (s *NotificationDispatchServiceImpl) endSkipStreak(subscription *entity.Subscription) {
+	s.skippedMutex.Lock()
+	defer s.skippedMutex.Unlock()
 
-	return 0, fmt.Errorf("currency %s not supported", currency)
+	delete(s.skipped, subscription.ID)
 }
 
-func (s *NotificationDispatchServiceImpl) fetchNews(keywords []string) []string {
-	// Mock implementation - replace with actual news API call
-	articles := []string{
-		"Bitcoin reaches new all-time high amid institutional adoption",
-		"Major tech companies announce blockchain partnerships",
-		"Cryptocurrency regulation updates from global markets",
-		"New DeFi protocol launches with innovative features",
-		"Market analysis: Crypto winter may be ending",
+func (s *NotificationDispatchServiceImpl) sendNotificationToSubscription(ctx context.Context, subscription *entity.Subscription, message string) error {
+	// Validate message length
+	if err := model.ValidateMessageString(message); err != nil {
+		errorMsg := err.Error()
+		_, logErr := s.logService.LogNotification(ctx, subscription.ID, message, "failed", &errorMsg)
+		if logErr != nil {
+			fmt.Printf("Failed to log notification error: %v\n", logErr)
+		}
+		return err
 	}
 
-	// Filter by keywords (simplified)
-	var filtered []string
-	for _, article := range articles {
-		for _, keyword := range keywords {
-			if strings.Contains(strings.ToLower(article), strings.ToLower(keyword)) {
-				filtered = append(filtered, article)
-				break
-			}
+	// Queue the message; the outbox sender retries it and writes the log row on delivery
+	if s.outbox != nil {
+		subscriptionID := subscription.ID
+		if _, err := s.outbox.Enqueue(ctx, &subscriptionID, subscription.ChatID, message); err != nil {
+			return fmt.Errorf("failed to queue telegram message: %w", err)
 		}
+		return nil
 	}
 
-	if len(filtered) == 0 {
-		return articles[:3] // Return first 3 if no matches
+	// Send via Telegram; the bot's outbound limiter keeps all workers within the global rate
+	if err := s.telegramService.SendMessage(subscription.ChatID, message); err != nil {
+		errorMsg := err.Error()
+		_, logErr := s.logService.LogNotification(ctx, subscription.ID, message, "failed", &errorMsg)
+		if logErr != nil {
+			fmt.Printf("Failed to log notification error: %v\n", logErr)
+		}
+		return fmt.Errorf("failed to send telegram message: %w", err)
 	}
 
-	return filtered
-}
-
-func (s *NotificationDispatchServiceImpl) fetchWeather(location string) string {
-	// Mock implementation - replace with actual weather API call
-	weathers := []string{
-		"Sunny, 72°F (22°C)\nWind: 5 mph\nHumidity: 45%",
-		"Partly cloudy, 68°F (20°C)\nWind: 8 mph\nHumidity: 55%",
-		"Light rain, 65°F (18°C)\nWind: 12 mph\nHumidity: 78%",
+	// Log successful notification
+	_, err := s.logService.LogNotification(ctx, subscription.ID, message, "sent", nil)
+	if err != nil {
+		fmt.Printf("Failed to log notification success: %v\n", err)
+		// Don't return error as the notification was sent successfully
 	}
 
-	// Return based on location hash (simplified)
-	index := len(location) % len(weathers)
-	return weathers[index]
+	return nil
 }
@@ -25,7 +25,7 @@ func NewNotificationLogService(notificationLogRepo repository.NotificationLogRep
 
 func (s *NotificationLogServiceImpl) LogNotification(ctx context.Context, subscriptionID int64, message, status string, errorMessage *string) (*entity.NotificationLog, error) {
 	log := &entity.NotificationLog{
-		SubscriptionID: subscriptionID,
+		SubscriptionID: &subscriptionID,
 		Message:        message,
 		Status:         status,
 		SentAt:         time.Now(),
@@ -39,6 +39,22 @@ func (s *NotificationLogServiceImpl) LogNotification(ctx context.Context, subscr
 	return log, nil
 }
 
+func (s *NotificationLogServiceImpl) LogChatMessage(ctx context.Context, chatID int64, message, status string, errorMessage *string) (*entity.NotificationLog, error) {
+	log := &entity.NotificationLog{
+		ChatID:       &chatID,
+		Message:      message,
+		Status:       status,
+		SentAt:       time.Now(),
+		ErrorMessage: errorMessage,
+	}
+
+	if err := s.notificationLogRepo.Create(ctx, log); err != nil {
+		return nil, fmt.Errorf("failed to create notification log: %w", err)
+	}
+
+	return log, nil
+}
+
 func (s *NotificationLogServiceImpl) GetSubscriptionLogs(ctx context.Context, subscriptionID int64, offset, limit int) ([]*entity.NotificationLog, error) {
 	logs, err := s.notificationLogRepo.GetBySubscriptionID(ctx, subscriptionID, offset, limit)
 	if err != nil {
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"go-messaging/entity"
@@ -11,15 +13,41 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrInvalidTypeCode is returned for codes that cannot be used in bot commands and buttons
+	ErrInvalidTypeCode = errors.New("invalid notification type code")
+	// ErrInvalidTypeInterval is returned for default intervals below one minute
+	ErrInvalidTypeInterval = errors.New("invalid notification type interval")
+	// ErrTypeHasSubscribers is returned when deleting a type that still has active subscriptions
+	ErrTypeHasSubscribers = errors.New("notification type has active subscriptions")
+)
+
+// typeCodePattern matches codes that fit in commands and callback data, e.g. price_alert
+var typeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
 // NotificationTypeServiceImpl implements NotificationTypeService
 type NotificationTypeServiceImpl struct {
 	notificationTypeRepo repository.NotificationTypeRepository
+	subscriptionRepo     repository.SubscriptionRepository
+	listener             NotificationTypeChangeListener
 }
 
 // NewNotificationTypeService creates a new notification type service
-func NewNotificationTypeService(notificationTypeRepo repository.NotificationTypeRepository) NotificationTypeService {
+func NewNotificationTypeService(notificationTypeRepo repository.NotificationTypeRepository, subscriptionRepo repository.SubscriptionRepository) NotificationTypeService {
 	return &NotificationTypeServiceImpl{
 		notificationTypeRepo: notificationTypeRepo,
+		subscriptionRepo:     subscriptionRepo,
+	}
+}
+
+func (s *NotificationTypeServiceImpl) SetChangeListener(listener NotificationTypeChangeListener) {
+	s.listener = listener
+}
+
+// changed tells the listener that a notification type was created or changed
+func (s *NotificationTypeServiceImpl) changed() {
+	if s.listener != nil {
+		s.listener.TriggerReload()
 	}
 }
 
@@ -61,7 +89,17 @@ func (s *NotificationTypeServiceImpl) GetTypeByID(ctx context.Context, id int) (
 	return notificationType, nil
 }
 
-func (s *NotificationTypeServiceImpl) CreateType(ctx context.Context, code, name string, description *string, defaultInterval int) (*entity.NotificationType, error) {
+func (s *NotificationTypeServiceImpl) CreateType(ctx context.Context, code, name string, description *string, defaultInterval int, schema entity.PreferenceSchema) (*entity.NotificationType, error) {
+	if !typeCodePattern.MatchString(code) {
+		return nil, fmt.Errorf("%w: %q must be lowercase letters, digits and underscores, starting with a letter", ErrInvalidTypeCode, code)
+	}
+	if defaultInterval < 1 {
+		return nil, fmt.Errorf("%w: %d minutes", ErrInvalidTypeInterval, defaultInterval)
+	}
+	if err := ValidatePreferenceSchema(schema); err != nil {
+		return nil, err
+	}
+
 	// Check if type with code already exists
 	_, err := s.notificationTypeRepo.GetByCode(ctx, code)
 	if err == nil {
@@ -77,6 +115,7 @@ func (s *NotificationTypeServiceImpl) CreateType(ctx context.Context, code, name
 		Description:            description,
 		DefaultIntervalMinutes: defaultInterval,
 		IsActive:               true,
+		PreferenceSchema:       schema,
 		CreatedAt:              time.Now(),
 		UpdatedAt:              time.Now(),
 	}
@@ -85,14 +124,24 @@ func (s *NotificationTypeServiceImpl) CreateType(ctx context.Context, code, name
 		return nil, fmt.Errorf("failed to create notification type: %w", err)
 	}
 
+	s.changed()
 	return notificationType, nil
 }
 
 func (s *NotificationTypeServiceImpl) UpdateType(ctx context.Context, notificationType *entity.NotificationType) error {
+	if notificationType.DefaultIntervalMinutes < 1 {
+		return fmt.Errorf("%w: %d minutes", ErrInvalidTypeInterval, notificationType.DefaultIntervalMinutes)
+	}
+	if err := ValidatePreferenceSchema(notificationType.PreferenceSchema); err != nil {
+		return err
+	}
+
 	notificationType.UpdatedAt = time.Now()
 	if err := s.notificationTypeRepo.Update(ctx, notificationType); err != nil {
 		return fmt.Errorf("failed to update notification type: %w", err)
 	}
+
+	s.changed()
 	return nil
 }
 
@@ -112,6 +161,7 @@ func (s *NotificationTypeServiceImpl) ActivateType(ctx context.Context, code str
 		return fmt.Errorf("failed to activate notification type: %w", err)
 	}
 
+	s.changed()
 	return nil
 }
 
@@ -131,5 +181,42 @@ func (s *NotificationTypeServiceImpl) DeactivateType(ctx context.Context, code s
 		return fmt.Errorf("failed to deactivate notification type: %w", err)
 	}
 
+	s.changed()
 	return nil
 }
+
+func (s *NotificationTypeServiceImpl) DeleteType(ctx context.Context, code string, force bool) error {
+	notificationType, err := s.notificationTypeRepo.GetByCode(ctx, code)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("notification type '%s' not found", code)
+		}
+		return fmt.Errorf("failed to get notification type: %w", err)
+	}
+
+	if !force {
+		counts, err := s.subscriptionRepo.CountActiveByType(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to count subscriptions: %w", err)
+		}
+		if count := counts[notificationType.ID]; count > 0 {
+			return fmt.Errorf("%w: '%s' has %d", ErrTypeHasSubscribers, code, count)
+		}
+	}
+
+	// Paused subscriptions go with the type either way
+	if err := s.notificationTypeRepo.DeleteWithSubscriptions(ctx, notificationType.ID); err != nil {
+		return fmt.Errorf("failed to delete notification type: %w", err)
+	}
+
+	s.changed()
+	return nil
+}
+
+func (s *NotificationTypeServiceImpl) GetSubscriberCounts(ctx context.Context) (map[int]int64, error) {
+	counts, err := s.subscriptionRepo.CountActiveByType(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+	return counts, nil
+}
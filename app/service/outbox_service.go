@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+	"go-messaging/repository"
+
+	"github.com/go-telegram/bot"
+	"gorm.io/gorm"
+)
+
+// OutboxConfig controls retries for queued messages
+type OutboxConfig struct {
+	// MaxAttempts is the number of failed sends before a message is dead-lettered
+	MaxAttempts int
+	// BaseBackoff is the delay after the first failure; it doubles on every retry
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// StaleAfter is how long a message may stay in sending before it is requeued
+	StaleAfter time.Duration
+}
+
+// DefaultOutboxConfig returns the retry settings used when none are configured
+func DefaultOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		MaxAttempts: 5,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  30 * time.Minute,
+		StaleAfter:  5 * time.Minute,
+	}
+}
+
+// OutboxServiceImpl implements OutboxService
+type OutboxServiceImpl struct {
+	outboxRepo      repository.OutboxRepository
+	logService      NotificationLogService
+	telegramService TelegramNotificationSender
+	config          OutboxConfig
+}
+
+// NewOutboxService creates a new outbox service. Sends are paced by the sender's outbound limiter.
+func NewOutboxService(
+	outboxRepo repository.OutboxRepository,
+	logService NotificationLogService,
+	telegramService TelegramNotificationSender,
+	config OutboxConfig,
+) OutboxService {
+	return &OutboxServiceImpl{
+		outboxRepo:      outboxRepo,
+		logService:      logService,
+		telegramService: telegramService,
+		config:          config,
+	}
+}
+
+func (s *OutboxServiceImpl) Enqueue(ctx context.Context, subscriptionID *int64, chatID int64, message string) (*entity.OutboundMessage, error) {
+	outbound := &entity.OutboundMessage{
+		SubscriptionID: subscriptionID,
+		ChatID:         chatID,
+		Message:        message,
+		Status:         entity.OutboundStatusPending,
+		NextAttemptAt:  time.Now(),
+	}
+
+	if err := s.outboxRepo.Create(ctx, outbound); err != nil {
+		return nil, fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return outbound, nil
+}
+
+func (s *OutboxServiceImpl) EnqueueChatMessage(ctx context.Context, chatID int64, message, parseMode string, buttons entity.InlineButtons) (*entity.OutboundMessage, error) {
+	outbound := &entity.OutboundMessage{
+		ChatID:        chatID,
+		Message:       message,
+		ParseMode:     parseMode,
+		Buttons:       buttons,
+		Status:        entity.OutboundStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+
+	if err := s.outboxRepo.Create(ctx, outbound); err != nil {
+		return nil, fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return outbound, nil
+}
+
+func (s *OutboxServiceImpl) GetMessage(ctx context.Context, id int64) (*entity.OutboundMessage, error) {
+	message, err := s.outboxRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("outbound message not found")
+		}
+		return nil, fmt.Errorf("failed to get outbound message: %w", err)
+	}
+	return message, nil
+}
+
+func (s *OutboxServiceImpl) ProcessDue(ctx context.Context, batchSize int) (int, error) {
+	messages, err := s.outboxRepo.ClaimDue(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due messages: %w", err)
+	}
+
+	for i, message := range messages {
+		if err := ctx.Err(); err != nil {
+			// Shutting down: hand the rest of the batch back to the queue
+			s.release(messages[i:], time.Now())
+			return i, err
+		}
+
+		messageID, err := s.send(message)
+		if err == nil {
+			s.markSent(ctx, message, messageID)
+			continue
+		}
+
+		// The chat is paused: try this message again once the pause ends
+		var paused *model.ChatPausedError
+		if errors.As(err, &paused) {
+			s.release([]*entity.OutboundMessage{message}, paused.Until)
+			continue
+		}
+
+		// Telegram asked us to back off: pause this batch until retry_after has passed
+		var tooMany *bot.TooManyRequestsError
+		if errors.As(err, &tooMany) {
+			retryAt := time.Now().Add(time.Duration(tooMany.RetryAfter) * time.Second)
+			slog.Warn("Telegram rate limit hit, pausing outbox", "retry_after", tooMany.RetryAfter, "chatID", message.ChatID)
+			s.release(messages[i:], retryAt)
+			return i + 1, nil
+		}
+
+		s.markFailed(ctx, message, err)
+	}
+
+	return len(messages), nil
+}
+
+func (s *OutboxServiceImpl) RecoverStale(ctx context.Context) (int, error) {
+	count, err := s.outboxRepo.ReleaseStale(ctx, s.config.StaleAfter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recover stale messages: %w", err)
+	}
+	return count, nil
+}
+
+func (s *OutboxServiceImpl) GetDeadLetters(ctx context.Context, offset, limit int) ([]*entity.OutboundMessage, int64, error) {
+	messages, err := s.outboxRepo.ListByStatus(ctx, entity.OutboundStatusDead, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	total, err := s.outboxRepo.CountByStatus(ctx, entity.OutboundStatusDead)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	return messages, total, nil
+}
+
+func (s *OutboxServiceImpl) Requeue(ctx context.Context, id int64) error {
+	message, err := s.outboxRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("outbound message not found")
+		}
+		return fmt.Errorf("failed to get outbound message: %w", err)
+	}
+
+	if message.Status != entity.OutboundStatusDead {
+		return fmt.Errorf("outbound message is not dead-lettered")
+	}
+
+	message.Status = entity.OutboundStatusPending
+	message.Attempts = 0
+	message.NextAttemptAt = time.Now()
+	message.LockedAt = nil
+
+	if err := s.outboxRepo.Update(ctx, message); err != nil {
+		return fmt.Errorf("failed to requeue outbound message: %w", err)
+	}
+
+	return nil
+}
+
+// send delivers one message with its parse mode and buttons
+func (s *OutboxServiceImpl) send(message *entity.OutboundMessage) (int, error) {
+	var keyboard *model.InlineKeyboardMarkup
+	if len(message.Buttons) > 0 {
+		keyboard = &model.InlineKeyboardMarkup{}
+		for _, row := range message.Buttons {
+			var keyboardRow []model.InlineKeyboardButton
+			for _, button := range row {
+				keyboardRow = append(keyboardRow, model.InlineKeyboardButton{
+					Text:         button.Text,
+					URL:          button.URL,
+					CallbackData: button.CallbackData,
+				})
+			}
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, keyboardRow)
+		}
+	}
+
+	return s.telegramService.SendFormattedMessage(message.ChatID, message.Message, message.ParseMode, keyboard)
+}
+
+// persistTimeout bounds the writes that record a send's outcome
+const persistTimeout = 10 * time.Second
+
+// markSent records a successful delivery
+func (s *OutboxServiceImpl) markSent(ctx context.Context, message *entity.OutboundMessage, telegramMessageID int) {
+	// The message went out, so record it even if we are shutting down; otherwise
+	// it stays in sending and is sent again once recovered
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancel()
+
+	now := time.Now()
+	message.Status = entity.OutboundStatusSent
+	message.Attempts++
+	message.SentAt = &now
+	message.LockedAt = nil
+	message.LastError = nil
+	if telegramMessageID != 0 {
+		message.TelegramMessageID = &telegramMessageID
+	}
+
+	if err := s.outboxRepo.Update(ctx, message); err != nil {
+		slog.Error("Failed to mark outbound message as sent", "id", message.ID, "error", err)
+	}
+
+	s.log(ctx, message, "sent", nil)
+}
+
+// log records the outcome in notification_logs, against the subscription when there is one
+func (s *OutboxServiceImpl) log(ctx context.Context, message *entity.OutboundMessage, status string, errorMessage *string) {
+	var err error
+	if message.SubscriptionID != nil {
+		_, err = s.logService.LogNotification(ctx, *message.SubscriptionID, message.Message, status, errorMessage)
+	} else {
+		_, err = s.logService.LogChatMessage(ctx, message.ChatID, message.Message, status, errorMessage)
+	}
+	if err != nil {
+		slog.Error("Failed to log outbound message", "id", message.ID, "status", status, "error", err)
+	}
+}
+
+// markFailed schedules a retry with exponential backoff or dead-letters the message
+func (s *OutboxServiceImpl) markFailed(ctx context.Context, message *entity.OutboundMessage, sendErr error) {
+	// Count the attempt even if we are shutting down
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancel()
+
+	errorMsg := sendErr.Error()
+	message.Attempts++
+	message.LastError = &errorMsg
+	message.LockedAt = nil
+
+	// Blocked bots and malformed messages will never succeed, so don't retry them
+	permanent := errors.Is(sendErr, bot.ErrorForbidden) || errors.Is(sendErr, bot.ErrorBadRequest)
+
+	if permanent || message.Attempts >= s.config.MaxAttempts {
+		message.Status = entity.OutboundStatusDead
+		slog.Warn("Outbound message dead-lettered", "id", message.ID, "attempts", message.Attempts, "error", sendErr)
+
+		s.log(ctx, message, "failed", &errorMsg)
+	} else {
+		message.Status = entity.OutboundStatusPending
+		message.NextAttemptAt = time.Now().Add(s.backoff(message.Attempts))
+	}
+
+	if err := s.outboxRepo.Update(ctx, message); err != nil {
+		slog.Error("Failed to update outbound message", "id", message.ID, "error", err)
+	}
+}
+
+// backoff returns the delay before the given retry attempt
+func (s *OutboxServiceImpl) backoff(attempts int) time.Duration {
+	delay := s.config.BaseBackoff
+	for i := 1; i < attempts && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	return delay
+}
+
+// release hands claimed messages back to the queue without counting an attempt
+func (s *OutboxServiceImpl) release(messages []*entity.OutboundMessage, nextAttemptAt time.Time) {
+	// Use a fresh context so messages are released even during shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+
+	for _, message := range messages {
+		message.Status = entity.OutboundStatusPending
+		message.NextAttemptAt = nextAttemptAt
+		message.LockedAt = nil
+		if err := s.outboxRepo.Update(ctx, message); err != nil {
+			slog.Error("Failed to release outbound message", "id", message.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go-messaging/entity"
+)
+
+// PreferenceField describes one subscription preference users can edit
+type PreferenceField struct {
+	Key     string
+	Label   string
+	Prompt  string   // question asked when the user picks this field
+	Options []string // fixed choices offered as buttons; empty means free text
+
+	// Set validates value and stores it in preferences
+	Set func(preferences *entity.SubscriptionPreferences, value string) error
+	// Get formats the current value for display
+	Get func(preferences *entity.SubscriptionPreferences) string
+}
+
+// clearValues are accepted by optional fields to remove the current value
+var clearValues = map[string]bool{"off": true, "none": true, "-": true}
+
+// schemaField builds the editable field for one property of a preference schema
+func schemaField(key string, property entity.PreferenceProperty, required bool) PreferenceField {
+	label := property.Title
+	if label == "" {
+		label = key
+	}
+
+	prompt := property.Description
+	if prompt == "" {
+		prompt = fmt.Sprintf("Send a new value for %s.", key)
+		if !required {
+			prompt += " Send 'off' to clear."
+		}
+	}
+
+	return PreferenceField{
+		Key:     key,
+		Label:   label,
+		Prompt:  prompt,
+		Options: property.Enum,
+		Set: func(preferences *entity.SubscriptionPreferences, text string) error {
+			values, err := preferenceValues(preferences)
+			if err != nil {
+				return err
+			}
+
+			if !required && clearValues[strings.ToLower(strings.TrimSpace(text))] {
+				delete(values, key)
+				return setPreferenceValues(preferences, values)
+			}
+
+			value, err := parsePreferenceText(key, property, text)
+			if err != nil {
+				return err
+			}
+			if value, err = normalizePreference(key, property, value); err != nil {
+				return err
+			}
+
+			values[key] = value
+			return setPreferenceValues(preferences, values)
+		},
+		Get: func(preferences *entity.SubscriptionPreferences) string {
+			values, err := preferenceValues(preferences)
+			if err != nil {
+				return ""
+			}
+			value, ok := values[key]
+			if !ok {
+				return ""
+			}
+			if number, isNumber := value.(float64); isNumber && property.Format == "price" {
+				return formatPrice(number, quoteCurrency(preferences))
+			}
+			if property.Type == "array" {
+				return strings.ReplaceAll(formatPreferenceValue(value), ",", ", ")
+			}
+			return formatPreferenceValue(value)
+		},
+	}
+}
+
+// PreferenceFields returns the editable preferences for a notification type,
+// its own properties first and then those every type accepts
+func PreferenceFields(notificationType *entity.NotificationType) []PreferenceField {
+	schema := EffectivePreferenceSchema(notificationType.PreferenceSchema)
+
+	required := make(map[string]bool)
+	for _, key := range schema.Required {
+		required[key] = true
+	}
+
+	var fields []PreferenceField
+	for _, key := range sortedKeys(notificationType.PreferenceSchema.Properties) {
+		if _, common := commonPreferenceProperties[key]; !common {
+			fields = append(fields, schemaField(key, schema.Properties[key], required[key]))
+		}
+	}
+	for _, key := range commonPreferenceOrder {
+		fields = append(fields, schemaField(key, schema.Properties[key], required[key]))
+	}
+	return fields
+}
+
+// FindPreferenceField returns the field with the given key for a notification type
+func FindPreferenceField(notificationType *entity.NotificationType, key string) (PreferenceField, bool) {
+	for _, field := range PreferenceFields(notificationType) {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return PreferenceField{}, false
+}
+
+// ParsePreferenceArgs parses key=value arguments such as "threshold=3500" into
+// preferences, validating each key against the schema of the notification type.
+// A word without '=' continues the previous value, so "location=New York" works.
+func ParsePreferenceArgs(notificationType *entity.NotificationType, args []string, preferences *entity.SubscriptionPreferences) error {
+	var keys, values []string
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			if len(values) == 0 {
+				return fmt.Errorf("expected key=value, got %q", arg)
+			}
+			values[len(values)-1] += " " + arg
+			continue
+		}
+		keys = append(keys, strings.ToLower(strings.TrimSpace(key)))
+		values = append(values, value)
+	}
+
+	for i, key := range keys {
+		field, ok := FindPreferenceField(notificationType, key)
+		if !ok {
+			return fmt.Errorf("unknown setting %q for %s, valid settings: %s", key, notificationType.Code, strings.Join(preferenceKeys(notificationType), ", "))
+		}
+		if err := field.Set(preferences, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preferenceKeys lists the setting keys accepted for a notification type
+func preferenceKeys(notificationType *entity.NotificationType) []string {
+	var keys []string
+	for _, field := range PreferenceFields(notificationType) {
+		keys = append(keys, field.Key)
+	}
+	return keys
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-messaging/entity"
+	"go-messaging/internal/schedule"
+)
+
+// ErrInvalidPreferenceSchema is returned for notification type schemas that cannot be applied to preferences
+var ErrInvalidPreferenceSchema = errors.New("invalid preference schema")
+
+// PreferenceError reports preferences that do not match a notification type's schema
+type PreferenceError struct {
+	Problems []string
+}
+
+func (e *PreferenceError) Error() string {
+	return fmt.Sprintf("invalid preferences: %s", strings.Join(e.Problems, "; "))
+}
+
+func floatPtr(value float64) *float64 { return &value }
+
+// currencyPattern matches a currency ticker such as BTC or USDC
+var currencyPattern = regexp.MustCompile(`^[A-Z0-9]{2,10}$`)
+
+// commonPreferenceProperties apply to every notification type. A type's own
+// schema may redeclare one of them, for example to narrow the interval bounds.
+var commonPreferenceProperties = map[string]entity.PreferenceProperty{
+	"interval": {
+		Type:        "integer",
+		Title:       "⏱️ Interval",
+		Description: "How often, in minutes? Send a number between 1 and 1440.",
+		Minimum:     floatPtr(1),
+		Maximum:     floatPtr(1440),
+	},
+	"schedule": {
+		Type:        "string",
+		Format:      "cron",
+		Title:       "🗓️ Schedule",
+		Description: "Send a cron expression in your time zone, e.g. '0 7 * * 1-5' for 07:00 on weekdays. Send 'off' to use the interval.",
+	},
+	"quiet_hours": {
+		Type:        "string",
+		Format:      "time_window",
+		Title:       "🌙 Quiet hours",
+		Description: "Send a window in which I should stay silent, e.g. 23:00-07:00. Send 'off' to clear.",
+	},
+}
+
+// commonPreferenceOrder is the order common properties are listed in, after the type's own
+var commonPreferenceOrder = []string{"interval", "schedule", "quiet_hours"}
+
+// structPreferences are the keys stored as SubscriptionPreferences fields rather than in Settings
+var structPreferences = map[string]bool{
+	"currency": true, "interval": true, "schedule": true, "quiet_hours": true,
+	"keywords": true, "threshold": true, "direction": true, "rearm_percent": true,
+}
+
+// EffectivePreferenceSchema returns schema merged with the properties every type accepts
+func EffectivePreferenceSchema(schema entity.PreferenceSchema) entity.PreferenceSchema {
+	merged := entity.PreferenceSchema{
+		Properties: make(map[string]entity.PreferenceProperty, len(commonPreferenceProperties)+len(schema.Properties)),
+		Required:   schema.Required,
+	}
+	for key, property := range commonPreferenceProperties {
+		merged.Properties[key] = property
+	}
+	for key, property := range schema.Properties {
+		merged.Properties[key] = property
+	}
+	return merged
+}
+
+// preferenceFormats are the formats each property type understands
+var preferenceFormats = map[string][]string{
+	"string":  {"currency", "cron", "time_window"},
+	"number":  {"price"},
+	"integer": {"price"},
+	"array":   nil,
+}
+
+// ValidatePreferenceSchema checks a schema before it is stored on a notification type:
+// every property has a known type and format, bounds are in order, defaults match
+// their property and required keys are declared. Problems are returned together,
+// wrapping ErrInvalidPreferenceSchema.
+func ValidatePreferenceSchema(schema entity.PreferenceSchema) error {
+	var problems []string
+	for _, key := range sortedKeys(schema.Properties) {
+		property := schema.Properties[key]
+
+		formats, ok := preferenceFormats[property.Type]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s has unknown type %q, use string, number, integer or array", key, property.Type))
+			continue
+		}
+		if property.Format != "" && !slices.Contains(formats, property.Format) {
+			problems = append(problems, fmt.Sprintf("%s has unknown format %q for type %s", key, property.Format, property.Type))
+		}
+		if property.Minimum != nil && property.Maximum != nil && *property.Minimum > *property.Maximum {
+			problems = append(problems, fmt.Sprintf("%s has minimum %v above maximum %v", key, *property.Minimum, *property.Maximum))
+		}
+		if len(property.Enum) > 0 && property.Type != "string" {
+			problems = append(problems, fmt.Sprintf("%s can only list enum values for type string", key))
+		}
+		if property.Default != nil {
+			if _, err := normalizePreference(key, property, property.Default); err != nil {
+				problems = append(problems, fmt.Sprintf("default of %s: %v", key, err))
+			}
+		}
+	}
+
+	effective := EffectivePreferenceSchema(schema)
+	for _, key := range schema.Required {
+		if _, ok := effective.Properties[key]; !ok {
+			problems = append(problems, fmt.Sprintf("required key %s is not a property", key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidPreferenceSchema, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ApplyPreferenceSchema fills in schema defaults for missing preferences and checks
+// the result: unknown keys, missing required keys, wrong types, enums, formats and
+// bounds. Problems are returned together as a *PreferenceError.
+func ApplyPreferenceSchema(schema entity.PreferenceSchema, preferences *entity.SubscriptionPreferences) error {
+	if preferences == nil {
+		return nil
+	}
+	schema = EffectivePreferenceSchema(schema)
+
+	values, err := preferenceValues(preferences)
+	if err != nil {
+		return err
+	}
+
+	for key, property := range schema.Properties {
+		if _, ok := values[key]; !ok && property.Default != nil {
+			values[key] = property.Default
+		}
+	}
+
+	var problems []string
+	for _, key := range sortedKeys(values) {
+		property, ok := schema.Properties[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not a setting of this notification type", key))
+			continue
+		}
+		normalized, err := normalizePreference(key, property, values[key])
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		values[key] = normalized
+	}
+
+	for _, key := range schema.Required {
+		if _, ok := values[key]; !ok {
+			problems = append(problems, fmt.Sprintf("%s is required", key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &PreferenceError{Problems: problems}
+	}
+
+	return setPreferenceValues(preferences, values)
+}
+
+// DescribePreferenceSchema renders the properties a type declares as one line each for chat messages
+func DescribePreferenceSchema(schema entity.PreferenceSchema) []string {
+	required := make(map[string]bool)
+	for _, key := range schema.Required {
+		required[key] = true
+	}
+
+	var lines []string
+	for _, key := range sortedKeys(schema.Properties) {
+		property := schema.Properties[key]
+
+		details := []string{property.Type}
+		if required[key] {
+			details = append(details, "required")
+		}
+		if len(property.Enum) > 0 {
+			details = append(details, strings.Join(property.Enum, "|"))
+		}
+		if property.Minimum != nil {
+			details = append(details, fmt.Sprintf("min %v", *property.Minimum))
+		}
+		if property.Maximum != nil {
+			details = append(details, fmt.Sprintf("max %v", *property.Maximum))
+		}
+		if property.Default != nil {
+			details = append(details, fmt.Sprintf("default %v", property.Default))
+		}
+
+		lines = append(lines, fmt.Sprintf("%s (%s)", key, strings.Join(details, ", ")))
+	}
+	return lines
+}
+
+// parsePreferenceText converts text typed by a user into a value of the property's type
+func parsePreferenceText(key string, property entity.PreferenceProperty, text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+
+	switch property.Type {
+	case "number", "integer":
+		if property.Format == "price" {
+			text = strings.TrimSpace(strings.TrimPrefix(text, "$"))
+		}
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number, got %q", key, text)
+		}
+		return number, nil
+	case "array":
+		var items []interface{}
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("%s needs at least one value", key)
+		}
+		return items, nil
+	default:
+		if text == "" {
+			return nil, fmt.Errorf("%s cannot be empty", key)
+		}
+		return text, nil
+	}
+}
+
+// normalizePreference checks a value against its property and returns it in canonical form
+func normalizePreference(key string, property entity.PreferenceProperty, value interface{}) (interface{}, error) {
+	switch property.Type {
+	case "number", "integer":
+		number, ok := value.(float64)
+		if !ok {
+			// Values kept in Settings arrive as strings
+			text, isText := value.(string)
+			parsed, err := strconv.ParseFloat(text, 64)
+			if !isText || err != nil {
+				return nil, fmt.Errorf("%s must be a number", key)
+			}
+			number = parsed
+		}
+		if property.Type == "integer" && number != math.Trunc(number) {
+			return nil, fmt.Errorf("%s must be a whole number", key)
+		}
+		if property.Minimum != nil && number < *property.Minimum {
+			return nil, fmt.Errorf("%s must be at least %v", key, *property.Minimum)
+		}
+		if property.Maximum != nil && number > *property.Maximum {
+			return nil, fmt.Errorf("%s must be at most %v", key, *property.Maximum)
+		}
+		return number, nil
+	case "array":
+		switch items := value.(type) {
+		case []interface{}:
+			return items, nil
+		case string:
+			// Lists kept in Settings are stored comma separated
+			var list []interface{}
+			for _, item := range strings.Split(items, ",") {
+				list = append(list, strings.TrimSpace(item))
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("%s must be a list", key)
+	default:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be text", key)
+		}
+		if len(property.Enum) > 0 {
+			for _, option := range property.Enum {
+				if strings.EqualFold(option, text) {
+					return option, nil
+				}
+			}
+			return nil, fmt.Errorf("%s must be one of %s", key, strings.Join(property.Enum, ", "))
+		}
+		switch property.Format {
+		case "currency":
+			// Whether the price source knows the ticker is checked when the subscription is saved
+			currency := strings.ToUpper(text)
+			if !currencyPattern.MatchString(currency) {
+				return nil, fmt.Errorf("invalid currency %q, send a ticker such as BTC or ETH", text)
+			}
+			return currency, nil
+		case "cron":
+			if _, err := schedule.ParseCron(text); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+		case "time_window":
+			if _, err := schedule.ParseQuietHours(text); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+		}
+		return text, nil
+	}
+}
+
+// preferenceValues flattens preferences, including Settings, into one map keyed by JSON name
+func preferenceValues(preferences *entity.SubscriptionPreferences) (map[string]interface{}, error) {
+	settings := preferences.Settings
+	withoutSettings := *preferences
+	withoutSettings.Settings = nil
+
+	data, err := json.Marshal(withoutSettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preferences: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode preferences: %w", err)
+	}
+
+	for key, value := range settings {
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// setPreferenceValues is the inverse of preferenceValues
+func setPreferenceValues(preferences *entity.SubscriptionPreferences, values map[string]interface{}) error {
+	fields := make(map[string]interface{})
+	settings := make(map[string]string)
+	for key, value := range values {
+		if structPreferences[key] {
+			fields[key] = value
+		} else {
+			settings[key] = formatPreferenceValue(value)
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+
+	var updated entity.SubscriptionPreferences
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return fmt.Errorf("failed to decode preferences: %w", err)
+	}
+	if len(settings) > 0 {
+		updated.Settings = settings
+	}
+
+	*preferences = updated
+	return nil
+}
+
+// formatPreferenceValue renders a flattened preference value as text
+func formatPreferenceValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/internal/price"
+	"go-messaging/repository"
+
+	"gorm.io/gorm"
+)
+
+// Price alert directions
+const (
+	PriceAlertAbove = "above"
+	PriceAlertBelow = "below"
+	PriceAlertBoth  = "both"
+)
+
+// ErrPriceAlertNotTriggered is returned when the price did not cross the threshold
+var ErrPriceAlertNotTriggered = Skip("price threshold not crossed")
+
+// PriceAlertContentProvider generates edge-triggered price threshold alerts
+type PriceAlertContentProvider struct {
+	prices      price.Client
+	alertStates repository.PriceAlertStateRepository
+
+	// pending holds the state behind alerts that have not been delivered yet
+	mutex   sync.Mutex
+	pending map[int64]*entity.PriceAlertState
+}
+
+// NewPriceAlertContentProvider creates a new price alert content provider
+func NewPriceAlertContentProvider(prices price.Client, alertStates repository.PriceAlertStateRepository) *PriceAlertContentProvider {
+	return &PriceAlertContentProvider{
+		prices:      prices,
+		alertStates: alertStates,
+		pending:     make(map[int64]*entity.PriceAlertState),
+	}
+}
+
+func (p *PriceAlertContentProvider) Code() string { return "price_alert" }
+
+// SkipPolicy advances LastNotifiedAt so prices are checked once per interval
+func (p *PriceAlertContentProvider) SkipPolicy() SkipPolicy { return SkipAdvance }
+
+// GetContent reports the current price against the threshold without touching alert state
+func (p *PriceAlertContentProvider) GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
+	settings := priceAlertSettingsFrom(preferences)
+
+	currentPrice, err := p.prices.GetSpotPrice(ctx, settings.currency, settings.quote)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s price: %w", settings.currency, err)
+	}
+
+	status := "Monitoring"
+	if currentPrice >= settings.threshold {
+		status = "Above threshold"
+	}
+
+	return fmt.Sprintf("📊 Price Alert: %s\n\nCurrent price: %s\nThreshold: %s\nDirection: %s\nStatus: %s\n\nUpdate time: %s",
+		settings.currency, formatPrice(currentPrice, settings.quote), formatPrice(settings.threshold, settings.quote),
+		settings.direction, status, time.Now().Format("15:04 MST")), nil
+}
+
+// GetSubscriptionContent fires once when the price crosses the threshold in the configured direction
+func (p *PriceAlertContentProvider) GetSubscriptionContent(ctx context.Context, subscription *entity.Subscription) (string, error) {
+	settings := priceAlertSettingsFrom(&subscription.Preferences)
+
+	currentPrice, err := p.prices.GetSpotPrice(ctx, settings.currency, settings.quote)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s price: %w", settings.currency, err)
+	}
+
+	previous, err := p.alertStates.GetBySubscriptionID(ctx, subscription.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to get price alert state: %w", err)
+	}
+
+	// A changed threshold invalidates the stored state, so start a new baseline
+	previousState := ""
+	if previous != nil && previous.Threshold == settings.threshold {
+		previousState = previous.State
+	}
+
+	band := settings.threshold * settings.rearmPercent / 100
+	nextState, fired := EvaluatePriceCrossing(previousState, currentPrice, settings.threshold, band, settings.direction)
+
+	state := &entity.PriceAlertState{
+		SubscriptionID: subscription.ID,
+		LastPrice:      currentPrice,
+		State:          nextState,
+		Threshold:      settings.threshold,
+	}
+
+	if !fired {
+		if err := p.alertStates.Save(ctx, state); err != nil {
+			return "", fmt.Errorf("failed to save price alert state: %w", err)
+		}
+		return "", ErrPriceAlertNotTriggered
+	}
+
+	// The crossing is only recorded once the alert is delivered; until then the next
+	// run still sees the old state and fires again
+	p.mutex.Lock()
+	p.pending[subscription.ID] = state
+	p.mutex.Unlock()
+
+	arrow := "📈"
+	verb := "rose above"
+	if nextState == PriceAlertBelow {
+		arrow = "📉"
+		verb = "fell below"
+	}
+
+	return fmt.Sprintf("🚨 Price Alert: %s\n\n%s %s %s %s\nCurrent price: %s\n\nAlert triggered at %s",
+		settings.currency, arrow, settings.currency, verb, formatPrice(settings.threshold, settings.quote),
+		formatPrice(currentPrice, settings.quote), time.Now().Format("15:04 MST")), nil
+}
+
+// Delivered saves the state behind an alert that was queued for delivery
+func (p *PriceAlertContentProvider) Delivered(ctx context.Context, subscription *entity.Subscription) error {
+	p.mutex.Lock()
+	state, ok := p.pending[subscription.ID]
+	delete(p.pending, subscription.ID)
+	p.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+	if err := p.alertStates.Save(ctx, state); err != nil {
+		return fmt.Errorf("failed to save price alert state: %w", err)
+	}
+	return nil
+}
+
+// Undelivered forgets the state behind an alert that could not be sent
+func (p *PriceAlertContentProvider) Undelivered(subscription *entity.Subscription) {
+	p.mutex.Lock()
+	delete(p.pending, subscription.ID)
+	p.mutex.Unlock()
+}
+
+// EvaluatePriceCrossing returns the next alert state and whether an alert fires.
+// The first observation only records a baseline. A crossing re-arms once the price
+// moves back past the threshold by more than band, so prices hovering around the
+// threshold do not trigger repeated alerts.
+func EvaluatePriceCrossing(previousState string, currentPrice, threshold, band float64, direction string) (string, bool) {
+	// Upward crossings happen at upper, downward crossings below lower
+	upper, lower := threshold, threshold-band
+	if direction == PriceAlertBelow {
+		upper, lower = threshold+band, threshold
+	}
+
+	switch previousState {
+	case PriceAlertBelow:
+		if currentPrice >= upper {
+			return PriceAlertAbove, direction != PriceAlertBelow
+		}
+		return PriceAlertBelow, false
+	case PriceAlertAbove:
+		if currentPrice < lower {
+			return PriceAlertBelow, direction != PriceAlertAbove
+		}
+		return PriceAlertAbove, false
+	default:
+		if currentPrice >= threshold {
+			return PriceAlertAbove, false
+		}
+		return PriceAlertBelow, false
+	}
+}
+
+// priceAlertSettings holds price alert preferences with defaults applied
+type priceAlertSettings struct {
+	currency     string
+	quote        string
+	threshold    float64
+	direction    string
+	rearmPercent float64
+}
+
+func priceAlertSettingsFrom(preferences *entity.SubscriptionPreferences) priceAlertSettings {
+	// Provide default values if preferences are missing or incomplete
+	settings := priceAlertSettings{
+		currency:  "BTC",
+		quote:     quoteCurrency(preferences),
+		threshold: 50000.0,
+		direction: PriceAlertAbove,
+	}
+
+	if preferences != nil {
+		if preferences.Currency != "" {
+			settings.currency = strings.ToUpper(preferences.Currency)
+		}
+		if preferences.Threshold > 0 {
+			settings.threshold = preferences.Threshold
+		}
+		switch strings.ToLower(preferences.Direction) {
+		case PriceAlertBelow:
+			settings.direction = PriceAlertBelow
+		case PriceAlertBoth:
+			settings.direction = PriceAlertBoth
+		}
+		if preferences.RearmPercent > 0 {
+			settings.rearmPercent = preferences.RearmPercent
+		}
+	}
+
+	return settings
+}
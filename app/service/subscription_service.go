@@ -2,38 +2,95 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"go-messaging/entity"
+	"go-messaging/internal/price"
 	"go-messaging/repository"
 
 	"gorm.io/gorm"
 )
 
+// UserNotApprovedError is returned when a user whose account is not approved tries to
+// subscribe or is sent a direct message
+type UserNotApprovedError struct {
+	Status string // the user's approval status: pending, rejected or disabled
+}
+
+func (e *UserNotApprovedError) Error() string {
+	return fmt.Sprintf("user is not approved (status: %s)", e.Status)
+}
+
+// ErrSubscriptionNotFound is returned when no subscription has the given ID
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
+// ErrChatAccessDenied is returned when a user may not manage the subscriptions of a chat
+var ErrChatAccessDenied = errors.New("only the chat's admins can manage its subscriptions")
+
+// ChatAdminChecker looks up whether a Telegram user administers a group or channel
+type ChatAdminChecker interface {
+	IsChatAdmin(ctx context.Context, chatID, userID int64) (bool, error)
+}
+
 // SubscriptionServiceImpl implements SubscriptionService
 type SubscriptionServiceImpl struct {
 	subscriptionRepo     repository.SubscriptionRepository
 	userRepo             repository.UserRepository
 	notificationTypeRepo repository.NotificationTypeRepository
 	notificationLogRepo  repository.NotificationLogRepository
+	priceClient          price.Client
+	chatAdmins           ChatAdminChecker
 }
 
-// NewSubscriptionService creates a new subscription service
+// NewSubscriptionService creates a new subscription service. Currency preferences
+// are checked against priceClient when one is given.
 func NewSubscriptionService(
 	subscriptionRepo repository.SubscriptionRepository,
 	userRepo repository.UserRepository,
 	notificationTypeRepo repository.NotificationTypeRepository,
 	notificationLogRepo repository.NotificationLogRepository,
+	priceClient price.Client,
 ) SubscriptionService {
 	return &SubscriptionServiceImpl{
 		subscriptionRepo:     subscriptionRepo,
 		userRepo:             userRepo,
 		notificationTypeRepo: notificationTypeRepo,
 		notificationLogRepo:  notificationLogRepo,
+		priceClient:          priceClient,
 	}
 }
 
+// SetChatAdminChecker sets how group and channel admins are recognised. Without one
+// only a chat's subscription owner and bot admins can manage a chat other than their own.
+func (s *SubscriptionServiceImpl) SetChatAdminChecker(checker ChatAdminChecker) {
+	s.chatAdmins = checker
+}
+
+// authorizeChat checks that user may manage the subscriptions of chatID: their own
+// private chat, a subscription they own, a chat they administer, or any chat for bot admins
+func (s *SubscriptionServiceImpl) authorizeChat(ctx context.Context, user *entity.User, chatID int64, subscription *entity.Subscription) error {
+	if chatID == user.TelegramUserID || user.Role == "admin" {
+		return nil
+	}
+	if subscription != nil && subscription.UserID == user.ID {
+		return nil
+	}
+
+	if s.chatAdmins != nil {
+		isAdmin, err := s.chatAdmins.IsChatAdmin(ctx, chatID, user.TelegramUserID)
+		if err != nil {
+			log.Printf("Failed to check whether user %d administers chat %d: %v", user.TelegramUserID, chatID, err)
+		} else if isAdmin {
+			return nil
+		}
+	}
+
+	return ErrChatAccessDenied
+}
+
 func (s *SubscriptionServiceImpl) Subscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (*entity.Subscription, error) {
 	// Get or create user
 	user, err := s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
@@ -44,6 +101,11 @@ func (s *SubscriptionServiceImpl) Subscribe(ctx context.Context, telegramUserID
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// Only approved users may subscribe
+	if user.ApprovalStatus != "approved" {
+		return nil, &UserNotApprovedError{Status: user.ApprovalStatus}
+	}
+
 	// Get notification type
 	notificationType, err := s.notificationTypeRepo.GetByCode(ctx, notificationTypeCode)
 	if err != nil {
@@ -57,17 +119,24 @@ func (s *SubscriptionServiceImpl) Subscribe(ctx context.Context, telegramUserID
 		return nil, fmt.Errorf("notification type '%s' is not active", notificationTypeCode)
 	}
 
-	// Check if subscription already exists
-	existing, err := s.subscriptionRepo.GetByUserAndType(ctx, user.ID, notificationType.ID)
+	// Check if the chat is already subscribed
+	existing, err := s.subscriptionRepo.GetByChatAndType(ctx, chatID, notificationType.ID)
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, fmt.Errorf("failed to check existing subscription: %w", err)
 	}
 
+	if err := s.authorizeChat(ctx, user, chatID, existing); err != nil {
+		return nil, err
+	}
+
 	if existing != nil {
-		// Update existing subscription
+		// Update existing subscription; whoever subscribes the chat last owns it
 		existing.IsActive = true
-		existing.ChatID = chatID
+		existing.UserID = user.ID
 		if preferences != nil {
+			if err := s.applyPreferences(ctx, notificationType.PreferenceSchema, preferences); err != nil {
+				return nil, err
+			}
 			existing.Preferences = *preferences
 		}
 		existing.UpdatedAt = time.Now()
@@ -97,6 +166,10 @@ func (s *SubscriptionServiceImpl) Subscribe(ctx context.Context, telegramUserID
 		}
 	}
 
+	if err := s.applyPreferences(ctx, notificationType.PreferenceSchema, &subscription.Preferences); err != nil {
+		return nil, err
+	}
+
 	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
 		return nil, fmt.Errorf("failed to create subscription: %w", err)
 	}
@@ -104,7 +177,7 @@ func (s *SubscriptionServiceImpl) Subscribe(ctx context.Context, telegramUserID
 	return subscription, nil
 }
 
-func (s *SubscriptionServiceImpl) Unsubscribe(ctx context.Context, telegramUserID int64, notificationTypeCode string) error {
+func (s *SubscriptionServiceImpl) Unsubscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string) error {
 	// Get user
 	user, err := s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
 	if err != nil {
@@ -123,8 +196,17 @@ func (s *SubscriptionServiceImpl) Unsubscribe(ctx context.Context, telegramUserI
 		return fmt.Errorf("failed to get notification type: %w", err)
 	}
 
+	existing, err := s.subscriptionRepo.GetByChatAndType(ctx, chatID, notificationType.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	if err := s.authorizeChat(ctx, user, chatID, existing); err != nil {
+		return err
+	}
+
 	// Delete subscription
-	if err := s.subscriptionRepo.DeleteByUserAndType(ctx, user.ID, notificationType.ID); err != nil {
+	if err := s.subscriptionRepo.DeleteByChatAndType(ctx, chatID, notificationType.ID); err != nil {
 		return fmt.Errorf("failed to delete subscription: %w", err)
 	}
 
@@ -149,6 +231,15 @@ func (s *SubscriptionServiceImpl) GetUserSubscriptions(ctx context.Context, tele
 	return subscriptions, nil
 }
 
+func (s *SubscriptionServiceImpl) GetChatSubscriptions(ctx context.Context, chatID int64) ([]*entity.Subscription, error) {
+	subscriptions, err := s.subscriptionRepo.GetActiveByChatID(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
 func (s *SubscriptionServiceImpl) GetActiveSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error) {
 	// Get notification type
 	notificationType, err := s.notificationTypeRepo.GetByCode(ctx, notificationTypeCode)
@@ -185,7 +276,16 @@ func (s *SubscriptionServiceImpl) GetDueSubscriptions(ctx context.Context, notif
 	return subscriptions, nil
 }
 
-func (s *SubscriptionServiceImpl) UpdatePreferences(ctx context.Context, telegramUserID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) error {
+func (s *SubscriptionServiceImpl) GetMinIntervals(ctx context.Context) (map[int]int, error) {
+	intervals, err := s.subscriptionRepo.GetMinIntervals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription intervals: %w", err)
+	}
+
+	return intervals, nil
+}
+
+func (s *SubscriptionServiceImpl) UpdatePreferences(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) error {
 	// Get user
 	user, err := s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
 	if err != nil {
@@ -205,7 +305,7 @@ func (s *SubscriptionServiceImpl) UpdatePreferences(ctx context.Context, telegra
 	}
 
 	// Get subscription
-	subscription, err := s.subscriptionRepo.GetByUserAndType(ctx, user.ID, notificationType.ID)
+	subscription, err := s.subscriptionRepo.GetByChatAndType(ctx, chatID, notificationType.ID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fmt.Errorf("subscription not found")
@@ -213,8 +313,15 @@ func (s *SubscriptionServiceImpl) UpdatePreferences(ctx context.Context, telegra
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
+	if err := s.authorizeChat(ctx, user, chatID, subscription); err != nil {
+		return err
+	}
+
 	// Update preferences
 	if preferences != nil {
+		if err := s.applyPreferences(ctx, notificationType.PreferenceSchema, preferences); err != nil {
+			return err
+		}
 		subscription.Preferences = *preferences
 	}
 	subscription.UpdatedAt = time.Now()
@@ -232,3 +339,162 @@ func (s *SubscriptionServiceImpl) MarkNotified(ctx context.Context, subscription
 	}
 	return nil
 }
+
+func (s *SubscriptionServiceImpl) ClaimDueSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error) {
+	notificationType, err := s.notificationTypeRepo.GetByCode(ctx, notificationTypeCode)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return []*entity.Subscription{}, nil
+		}
+		return nil, fmt.Errorf("failed to get notification type: %w", err)
+	}
+
+	subscriptions, err := s.subscriptionRepo.ClaimDueForNotification(ctx, notificationType.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (s *SubscriptionServiceImpl) ReleaseClaim(ctx context.Context, subscription *entity.Subscription) error {
+	if err := s.subscriptionRepo.SetLastNotified(ctx, subscription.ID, subscription.LastNotifiedAt); err != nil {
+		return fmt.Errorf("failed to release subscription claim: %w", err)
+	}
+	return nil
+}
+
+func (s *SubscriptionServiceImpl) ListSubscriptions(ctx context.Context, filter SubscriptionFilter, offset, limit int) ([]*entity.Subscription, int64, error) {
+	repoFilter := repository.SubscriptionFilter{
+		UserID:   filter.UserID,
+		ChatID:   filter.ChatID,
+		IsActive: filter.IsActive,
+	}
+
+	// An unknown owner or type matches nothing
+	if filter.TelegramUserID != nil {
+		user, err := s.userRepo.GetByTelegramUserID(ctx, *filter.TelegramUserID)
+		if err == gorm.ErrRecordNotFound {
+			return []*entity.Subscription{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get user: %w", err)
+		}
+		if filter.UserID != nil && *filter.UserID != user.ID {
+			return []*entity.Subscription{}, 0, nil
+		}
+		repoFilter.UserID = &user.ID
+	}
+
+	if filter.NotificationTypeCode != "" {
+		notificationType, err := s.notificationTypeRepo.GetByCode(ctx, filter.NotificationTypeCode)
+		if err == gorm.ErrRecordNotFound {
+			return []*entity.Subscription{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get notification type: %w", err)
+		}
+		repoFilter.NotificationTypeID = &notificationType.ID
+	}
+
+	subscriptions, total, err := s.subscriptionRepo.List(ctx, repoFilter, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return subscriptions, total, nil
+}
+
+// applyPreferences applies a notification type's preference schema and checks that
+// the price source knows every currency the preferences name
+func (s *SubscriptionServiceImpl) applyPreferences(ctx context.Context, schema entity.PreferenceSchema, preferences *entity.SubscriptionPreferences) error {
+	if err := ApplyPreferenceSchema(schema, preferences); err != nil {
+		return err
+	}
+	if s.priceClient == nil || preferences == nil {
+		return nil
+	}
+
+	values, err := preferenceValues(preferences)
+	if err != nil {
+		return err
+	}
+
+	quote := quoteCurrency(preferences)
+	var problems []string
+	for _, key := range sortedKeys(schema.Properties) {
+		currency, ok := values[key].(string)
+		if schema.Properties[key].Format != "currency" || !ok || currency == "" {
+			continue
+		}
+
+		// Only an unsupported pair is the user's mistake; an unreachable price
+		// source must not stop them from saving their preferences
+		_, err := s.priceClient.GetSpotPrice(ctx, currency, quote)
+		if price.IsUnsupportedPair(err) {
+			problems = append(problems, fmt.Sprintf("unsupported currency %q, no %s-%s price is available", currency, currency, quote))
+		} else if err != nil {
+			log.Printf("Could not check currency %s: %v", currency, err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &PreferenceError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *SubscriptionServiceImpl) GetSubscription(ctx context.Context, id int64) (*entity.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (s *SubscriptionServiceImpl) UpdateSubscriptionPreferences(ctx context.Context, id int64, preferences *entity.SubscriptionPreferences) (*entity.Subscription, error) {
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPreferences(ctx, subscription.NotificationType.PreferenceSchema, preferences); err != nil {
+		return nil, err
+	}
+	subscription.Preferences = *preferences
+	subscription.UpdatedAt = time.Now()
+
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update subscription preferences: %w", err)
+	}
+	return subscription, nil
+}
+
+func (s *SubscriptionServiceImpl) SetSubscriptionActive(ctx context.Context, id int64, active bool) (*entity.Subscription, error) {
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.IsActive = active
+	subscription.UpdatedAt = time.Now()
+
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (s *SubscriptionServiceImpl) DeleteSubscription(ctx context.Context, id int64) error {
+	if _, err := s.GetSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+
+	"github.com/google/uuid"
+)
+
+// NotifyPendingUser sends every approved admin a message about a new user waiting for
+// approval, with buttons to approve, reject or view them. Each admin is told once.
+func (s *TelegramAdminService) NotifyPendingUser(ctx context.Context, user *entity.User) {
+	admins, err := s.adminService.GetAdmins(ctx)
+	if err != nil {
+		slog.Error("Failed to get admins to notify about pending user", "userID", user.ID, "error", err)
+		return
+	}
+
+	message := pendingUserSummary(user) + "\n\nPending users are removed after 6 hours."
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{
+			{
+				{Text: "✅ Approve", CallbackData: fmt.Sprintf("approve_user:%s", user.ID.String())},
+				{Text: "❌ Reject", CallbackData: fmt.Sprintf("reject_user:%s", user.ID.String())},
+			},
+			{
+				{Text: "👁️ View", CallbackData: fmt.Sprintf("view_user:%s", user.ID.String())},
+			},
+		},
+	}
+
+	for _, admin := range admins {
+		// Admins reach the bot in their private chat, whose ID is their Telegram ID
+		chatID := admin.TelegramUserID
+
+		claimed, err := s.adminService.ClaimApprovalNotification(ctx, user.ID, chatID)
+		if err != nil || !claimed {
+			continue
+		}
+
+		messageID, err := s.telegramService.SendMessageWithKeyboardID(chatID, message, keyboard)
+		if err != nil {
+			slog.Warn("Failed to notify admin about pending user", "adminID", admin.ID, "userID", user.ID, "error", err)
+			s.adminService.ReleaseApprovalNotification(ctx, user.ID, chatID)
+			continue
+		}
+
+		s.adminService.RecordApprovalNotification(ctx, user.ID, chatID, messageID)
+	}
+}
+
+// closeApprovalNotifications edits the messages admins were sent about a user to show
+// what happened and removes their buttons. It reports whether pressed, the message the
+// acting admin pressed a button on, was one of them.
+func (s *TelegramAdminService) closeApprovalNotifications(ctx context.Context, userID uuid.UUID, outcome string, pressed *model.Message) bool {
+	notifications, err := s.adminService.TakeApprovalNotifications(ctx, userID)
+	if err != nil || len(notifications) == 0 {
+		return false
+	}
+
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		slog.Error("Failed to get user for approval notifications", "userID", userID, "error", err)
+		return false
+	}
+
+	message := pendingUserSummary(user) + "\n\n" + outcome
+	keyboard := &model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{
+			{
+				{Text: "👁️ View", CallbackData: fmt.Sprintf("view_user:%s", user.ID.String())},
+			},
+		},
+	}
+
+	wasPressed := false
+	for _, notification := range notifications {
+		if pressed != nil && pressed.Chat.ID == notification.AdminChatID && pressed.MessageID == notification.MessageID {
+			wasPressed = true
+		}
+		if notification.MessageID == 0 {
+			continue
+		}
+
+		if err := s.telegramService.EditMessageWithKeyboard(notification.AdminChatID, notification.MessageID, message, keyboard); err != nil {
+			slog.Warn("Failed to update approval notification", "adminChatID", notification.AdminChatID, "userID", userID, "error", err)
+		}
+	}
+	return wasPressed
+}
+
+// pendingUserSummary describes a user waiting for approval
+func pendingUserSummary(user *entity.User) string {
+	username := "N/A"
+	if user.Username != nil && *user.Username != "" {
+		username = "@" + *user.Username
+	}
+
+	return fmt.Sprintf(`👤 New user waiting for approval
+
+Name: %s
+Username: %s
+Telegram ID: %d
+Joined: %s`,
+		getDisplayName(user), username, user.TelegramUserID, user.CreatedAt.Format("2006-01-02 15:04"))
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"go-messaging/entity"
 	"go-messaging/model"
 	"log/slog"
 	"strconv"
@@ -12,20 +13,24 @@ import (
 )
 
 type TelegramAdminService struct {
-	telegramService TelegramNotificationSender
-	adminService    AdminServiceInterface
-	userService     UserService
+	telegramService         TelegramNotificationSender
+	adminService            AdminServiceInterface
+	userService             UserService
+	notificationTypeService NotificationTypeService
+	pendingCreates          pendingTypeCreates
 }
 
 func NewTelegramAdminService(
 	telegramService TelegramNotificationSender,
 	adminService AdminServiceInterface,
 	userService UserService,
+	notificationTypeService NotificationTypeService,
 ) *TelegramAdminService {
 	return &TelegramAdminService{
-		telegramService: telegramService,
-		adminService:    adminService,
-		userService:     userService,
+		telegramService:         telegramService,
+		adminService:            adminService,
+		userService:             userService,
+		notificationTypeService: notificationTypeService,
 	}
 }
 
@@ -45,17 +50,22 @@ func (s *TelegramAdminService) HandleAdminCommand(ctx context.Context, message m
 	parts := strings.Fields(message.Text)
 	command := parts[0]
 
+	// Commands always answer with a new message
 	switch command {
 	case "/admin":
-		s.showAdminMenu(ctx, message.Chat.ID)
+		s.showAdminMenu(ctx, message.Chat.ID, 0)
 	case "/admin_pending":
-		s.showPendingUsers(ctx, message.Chat.ID)
+		s.showPendingUsers(ctx, message.Chat.ID, 0, 0)
 	case "/admin_approved":
-		s.showApprovedUsers(ctx, message.Chat.ID)
+		s.showApprovedUsers(ctx, message.Chat.ID, 0, 0)
 	case "/admin_stats":
-		s.showUserStats(ctx, message.Chat.ID)
+		s.showUserStats(ctx, message.Chat.ID, 0)
 	case "/admin_cleanup":
-		s.cleanupPendingUsers(ctx, message.Chat.ID)
+		s.cleanupPendingUsers(ctx, message.Chat.ID, 0)
+	case "/admin_find":
+		s.findUsers(ctx, message.Chat.ID, strings.Join(parts[1:], " "))
+	case "/admin_types", "/admin_type_create", "/admin_type_toggle", "/admin_type_interval":
+		s.handleTypeCommand(ctx, message, command, parts[1:])
 	default:
 		s.telegramService.SendMessage(message.Chat.ID, "❓ Unknown admin command. Use /admin to see available options.")
 	}
@@ -76,7 +86,7 @@ func (s *TelegramAdminService) HandleCallbackQuery(ctx context.Context, callback
 	data := callback.Data
 	parts := strings.Split(data, ":")
 
-	if len(parts) < 2 {
+	if len(parts) < 2 || callback.Message == nil {
 		s.answerCallbackQuery(callback.ID, "❌ Invalid callback data")
 		return
 	}
@@ -86,7 +96,9 @@ func (s *TelegramAdminService) HandleCallbackQuery(ctx context.Context, callback
 
 	switch action {
 	case "admin_menu":
-		s.handleAdminMenuCallback(ctx, callback, param)
+		s.handleAdminMenuCallback(ctx, callback, parts[1:])
+	case "admin_type":
+		s.handleTypeCallback(ctx, callback, parts[1:])
 	case "approve_user":
 		s.handleUserApproval(ctx, callback, param, true)
 	case "reject_user":
@@ -102,7 +114,27 @@ func (s *TelegramAdminService) HandleCallbackQuery(ctx context.Context, callback
 	}
 }
 
-func (s *TelegramAdminService) showAdminMenu(ctx context.Context, chatID int64) {
+// adminPageSize is how many users one page of an admin list shows
+const adminPageSize = 5
+
+// backToMenu is the keyboard row that returns to the admin menu
+var backToMenu = []model.InlineKeyboardButton{
+	{Text: "🔙 Back to Menu", CallbackData: "admin_menu:main"},
+}
+
+// pageButtons returns the paging row of a list view, or nil when there is one page only
+func pageButtons(view string, page int, hasNext bool) []model.InlineKeyboardButton {
+	var row []model.InlineKeyboardButton
+	if page > 0 {
+		row = append(row, model.InlineKeyboardButton{Text: "◀ Prev", CallbackData: fmt.Sprintf("admin_menu:%s:%d", view, page-1)})
+	}
+	if hasNext {
+		row = append(row, model.InlineKeyboardButton{Text: "Next ▶", CallbackData: fmt.Sprintf("admin_menu:%s:%d", view, page+1)})
+	}
+	return row
+}
+
+func (s *TelegramAdminService) showAdminMenu(ctx context.Context, chatID int64, messageID int) {
 	keyboard := model.InlineKeyboardMarkup{
 		InlineKeyboard: [][]model.InlineKeyboardButton{
 			{
@@ -113,39 +145,63 @@ func (s *TelegramAdminService) showAdminMenu(ctx context.Context, chatID int64)
 				{Text: "📊 User Stats", CallbackData: "admin_menu:stats"},
 				{Text: "🧹 Cleanup", CallbackData: "admin_menu:cleanup"},
 			},
+			{
+				{Text: "🧩 Notification Types", CallbackData: "admin_menu:types"},
+				{Text: "✖️ Close", CallbackData: "admin_menu:close"},
+			},
 		},
 	}
 
 	message := "🔧 **Admin Panel**\n\n" +
-		"Welcome to the admin panel. Choose an option below:"
+		"Welcome to the admin panel. Choose an option below, or look a user up with /admin_find <username|id>."
 
-	s.sendMessageWithKeyboard(chatID, message, keyboard)
+	s.render(chatID, messageID, message, keyboard)
 }
 
-func (s *TelegramAdminService) showPendingUsers(ctx context.Context, chatID int64) {
-	users, err := s.adminService.GetPendingUsers(ctx)
+// loadUserPage loads a page of the users with a status. A page past the end, e.g. after
+// the last user on it was approved, loads the last page instead.
+func (s *TelegramAdminService) loadUserPage(ctx context.Context, status string, page int) ([]entity.User, int, int, error) {
+	users, total, err := s.adminService.GetUsersPage(ctx, status, page*adminPageSize, adminPageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	pages := int((total + adminPageSize - 1) / adminPageSize)
+	if len(users) == 0 && page > 0 && pages > 0 {
+		page = pages - 1
+		users, total, err = s.adminService.GetUsersPage(ctx, status, page*adminPageSize, adminPageSize)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		pages = int((total + adminPageSize - 1) / adminPageSize)
+	}
+	return users, page, pages, nil
+}
+
+func (s *TelegramAdminService) showPendingUsers(ctx context.Context, chatID int64, messageID int, page int) {
+	users, page, pages, err := s.loadUserPage(ctx, "pending", page)
 	if err != nil {
 		slog.Error("Failed to get pending users", "error", err)
-		s.telegramService.SendMessage(chatID, "❌ Failed to get pending users")
+		s.render(chatID, messageID, "❌ Failed to get pending users", model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+		})
 		return
 	}
 
 	if len(users) == 0 {
-		s.telegramService.SendMessage(chatID, "✨ No pending users found!")
+		s.render(chatID, messageID, "✨ No pending users found!", model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+		})
 		return
 	}
 
-	message := "📋 **Pending Users** (" + strconv.Itoa(len(users)) + "):\n\n"
+	message := fmt.Sprintf("📋 **Pending Users** - page %d/%d:\n\n", page+1, pages)
 
 	keyboard := model.InlineKeyboardMarkup{
 		InlineKeyboard: [][]model.InlineKeyboardButton{},
 	}
 
-	for i, user := range users {
-		if i >= 10 { // Limit to 10 users per message
-			break
-		}
-
+	for _, user := range users {
 		username := "N/A"
 		if user.Username != nil {
 			username = *user.Username
@@ -162,38 +218,40 @@ func (s *TelegramAdminService) showPendingUsers(ctx context.Context, chatID int6
 
 		// Add action buttons for each user
 		row := []model.InlineKeyboardButton{
-			{Text: "✅ Approve", CallbackData: fmt.Sprintf("approve_user:%s", user.ID.String())},
+			{Text: fmt.Sprintf("✅ Approve %s", firstName), CallbackData: fmt.Sprintf("approve_user:%s", user.ID.String())},
 			{Text: "❌ Reject", CallbackData: fmt.Sprintf("reject_user:%s", user.ID.String())},
 		}
 		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 	}
 
-	if len(users) > 10 {
-		message += fmt.Sprintf("... and %d more users", len(users)-10)
+	if row := pageButtons("pending", page, page < pages-1); row != nil {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 	}
 
 	// Add back button
-	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
-		{Text: "🔙 Back to Menu", CallbackData: "admin_menu:main"},
-	})
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, backToMenu)
 
-	s.sendMessageWithKeyboard(chatID, message, keyboard)
+	s.render(chatID, messageID, message, keyboard)
 }
 
-func (s *TelegramAdminService) showApprovedUsers(ctx context.Context, chatID int64) {
-	users, err := s.adminService.GetApprovedUsers(ctx, 10)
+func (s *TelegramAdminService) showApprovedUsers(ctx context.Context, chatID int64, messageID int, page int) {
+	users, page, pages, err := s.loadUserPage(ctx, "approved", page)
 	if err != nil {
 		slog.Error("Failed to get approved users", "error", err)
-		s.telegramService.SendMessage(chatID, "❌ Failed to get approved users")
+		s.render(chatID, messageID, "❌ Failed to get approved users", model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+		})
 		return
 	}
 
 	if len(users) == 0 {
-		s.telegramService.SendMessage(chatID, "📭 No approved users found!")
+		s.render(chatID, messageID, "📭 No approved users found!", model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+		})
 		return
 	}
 
-	message := "✅ **Approved Users** (showing last " + strconv.Itoa(len(users)) + "):\n\n"
+	message := fmt.Sprintf("✅ **Approved Users** - page %d/%d:\n\n", page+1, pages)
 
 	keyboard := model.InlineKeyboardMarkup{
 		InlineKeyboard: [][]model.InlineKeyboardButton{},
@@ -221,25 +279,82 @@ func (s *TelegramAdminService) showApprovedUsers(ctx context.Context, chatID int
 
 		// Add action button for each user
 		row := []model.InlineKeyboardButton{
-			{Text: "🚫 Disable", CallbackData: fmt.Sprintf("disable_user:%s", user.ID.String())},
+			{Text: fmt.Sprintf("🚫 Disable %s", firstName), CallbackData: fmt.Sprintf("disable_user:%s", user.ID.String())},
 			{Text: "👁️ View", CallbackData: fmt.Sprintf("view_user:%s", user.ID.String())},
 		}
 		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 	}
 
+	if row := pageButtons("approved", page, page < pages-1); row != nil {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+	}
+
 	// Add back button
-	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
-		{Text: "🔙 Back to Menu", CallbackData: "admin_menu:main"},
-	})
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, backToMenu)
+
+	s.render(chatID, messageID, message, keyboard)
+}
+
+// adminFindLimit is how many matches /admin_find lists
+const adminFindLimit = 10
+
+// findUsers answers /admin_find: a single match opens the user, several are listed
+// with a button each
+func (s *TelegramAdminService) findUsers(ctx context.Context, chatID int64, query string) {
+	if query == "" {
+		s.telegramService.SendMessage(chatID, "Usage: /admin_find <username|name|telegram id|user id>")
+		return
+	}
+
+	users, err := s.adminService.FindUsers(ctx, query, adminFindLimit)
+	if err != nil {
+		s.telegramService.SendMessage(chatID, "❌ Failed to search users")
+		return
+	}
+
+	if len(users) == 0 {
+		s.render(chatID, 0, fmt.Sprintf("🔎 No users match \"%s\"", query), model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+		})
+		return
+	}
+
+	if len(users) == 1 {
+		s.showUser(ctx, chatID, 0, users[0].ID)
+		return
+	}
+
+	message := fmt.Sprintf("🔎 **Users matching \"%s\"**", query)
+	if len(users) == adminFindLimit {
+		message += fmt.Sprintf(" (first %d)", adminFindLimit)
+	}
+	message += ":\n\n"
+
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{},
+	}
+
+	for _, user := range users {
+		message += fmt.Sprintf("👤 %s - %s\n", getDisplayName(&user), user.ApprovalStatus)
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+			{Text: fmt.Sprintf("👁️ %s", getDisplayName(&user)), CallbackData: fmt.Sprintf("view_user:%s", user.ID.String())},
+		})
+	}
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, backToMenu)
 
-	s.sendMessageWithKeyboard(chatID, message, keyboard)
+	s.render(chatID, 0, message, keyboard)
 }
 
-func (s *TelegramAdminService) showUserStats(ctx context.Context, chatID int64) {
+func (s *TelegramAdminService) showUserStats(ctx context.Context, chatID int64, messageID int) {
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+	}
+
 	stats, err := s.adminService.GetUserStats(ctx)
 	if err != nil {
 		slog.Error("Failed to get user stats", "error", err)
-		s.telegramService.SendMessage(chatID, "❌ Failed to get user statistics")
+		s.render(chatID, messageID, "❌ Failed to get user statistics", keyboard)
 		return
 	}
 
@@ -251,50 +366,54 @@ func (s *TelegramAdminService) showUserStats(ctx context.Context, chatID int64)
 	message += fmt.Sprintf("👑 Admins: %d\n", stats["admins"])
 	message += fmt.Sprintf("\n📈 Total Users: %d", stats["pending"]+stats["approved"]+stats["rejected"]+stats["disabled"])
 
+	s.render(chatID, messageID, message, keyboard)
+}
+
+func (s *TelegramAdminService) cleanupPendingUsers(ctx context.Context, chatID int64, messageID int) {
 	keyboard := model.InlineKeyboardMarkup{
-		InlineKeyboard: [][]model.InlineKeyboardButton{
-			{
-				{Text: "🔙 Back to Menu", CallbackData: "admin_menu:main"},
-			},
-		},
+		InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
 	}
 
-	s.sendMessageWithKeyboard(chatID, message, keyboard)
-}
-
-func (s *TelegramAdminService) cleanupPendingUsers(ctx context.Context, chatID int64) {
 	count, err := s.adminService.CleanupPendingUsers(ctx)
 	if err != nil {
 		slog.Error("Failed to cleanup pending users", "error", err)
-		s.telegramService.SendMessage(chatID, "❌ Failed to cleanup pending users")
+		s.render(chatID, messageID, "❌ Failed to cleanup pending users", keyboard)
 		return
 	}
 
 	message := fmt.Sprintf("🧹 **Cleanup Complete**\n\nRemoved %d pending users older than 6 hours.", count)
 
-	keyboard := model.InlineKeyboardMarkup{
-		InlineKeyboard: [][]model.InlineKeyboardButton{
-			{
-				{Text: "🔙 Back to Menu", CallbackData: "admin_menu:main"},
-			},
-		},
-	}
-
-	s.sendMessageWithKeyboard(chatID, message, keyboard)
+	s.render(chatID, messageID, message, keyboard)
 }
 
-func (s *TelegramAdminService) handleAdminMenuCallback(ctx context.Context, callback model.CallbackQuery, param string) {
-	switch param {
+// handleAdminMenuCallback handles admin_menu:<view>[:<page>] buttons by redrawing the
+// message they are on
+func (s *TelegramAdminService) handleAdminMenuCallback(ctx context.Context, callback model.CallbackQuery, args []string) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	page := 0
+	if len(args) > 1 {
+		if n, err := strconv.Atoi(args[1]); err == nil && n >= 0 {
+			page = n
+		}
+	}
+
+	switch args[0] {
 	case "main":
-		s.showAdminMenu(ctx, callback.Message.Chat.ID)
+		s.showAdminMenu(ctx, chatID, messageID)
 	case "pending":
-		s.showPendingUsers(ctx, callback.Message.Chat.ID)
+		s.showPendingUsers(ctx, chatID, messageID, page)
 	case "approved":
-		s.showApprovedUsers(ctx, callback.Message.Chat.ID)
+		s.showApprovedUsers(ctx, chatID, messageID, page)
 	case "stats":
-		s.showUserStats(ctx, callback.Message.Chat.ID)
+		s.showUserStats(ctx, chatID, messageID)
 	case "cleanup":
-		s.cleanupPendingUsers(ctx, callback.Message.Chat.ID)
+		s.cleanupPendingUsers(ctx, chatID, messageID)
+	case "types":
+		s.showTypes(ctx, chatID, messageID, "")
+	case "close":
+		s.closeMessage(chatID, messageID)
 	}
 	s.answerCallbackQuery(callback.ID, "")
 }
@@ -326,14 +445,26 @@ func (s *TelegramAdminService) handleUserApproval(ctx context.Context, callback
 	}
 
 	if err != nil {
+		// Another admin may have got there first
+		if user, getErr := s.userService.GetUserByID(ctx, userID); getErr == nil && user.ApprovalStatus != "pending" {
+			s.closeApprovalNotifications(ctx, userID, fmt.Sprintf("ℹ️ Already %s", user.ApprovalStatus), callback.Message)
+			s.answerCallbackQuery(callback.ID, fmt.Sprintf("ℹ️ User is already %s", user.ApprovalStatus))
+			return
+		}
 		s.answerCallbackQuery(callback.ID, fmt.Sprintf("❌ Failed to %s user", action))
 		return
 	}
 
 	s.answerCallbackQuery(callback.ID, fmt.Sprintf("%s user successfully", actionText))
 
+	// Update the messages every admin got about this user
+	outcome := fmt.Sprintf("%s by %s", actionText, getDisplayName(admin))
+	if s.closeApprovalNotifications(ctx, userID, outcome, callback.Message) {
+		return
+	}
+
 	// Refresh the pending users list
-	s.showPendingUsers(ctx, callback.Message.Chat.ID)
+	s.showPendingUsers(ctx, callback.Message.Chat.ID, callback.Message.MessageID, 0)
 }
 
 func (s *TelegramAdminService) handleUserDisable(ctx context.Context, callback model.CallbackQuery, userIDStr string) {
@@ -358,8 +489,8 @@ func (s *TelegramAdminService) handleUserDisable(ctx context.Context, callback m
 
 	s.answerCallbackQuery(callback.ID, "🚫 User disabled successfully")
 
-	// Refresh the approved users list
-	s.showApprovedUsers(ctx, callback.Message.Chat.ID)
+	// Show the user's new status, with the button to enable them again
+	s.showUser(ctx, callback.Message.Chat.ID, callback.Message.MessageID, userID)
 }
 
 func (s *TelegramAdminService) handleUserEnable(ctx context.Context, callback model.CallbackQuery, userIDStr string) {
@@ -383,6 +514,9 @@ func (s *TelegramAdminService) handleUserEnable(ctx context.Context, callback mo
 	}
 
 	s.answerCallbackQuery(callback.ID, "✅ User enabled successfully")
+
+	// Show the user's new status, with the button to disable them again
+	s.showUser(ctx, callback.Message.Chat.ID, callback.Message.MessageID, userID)
 }
 
 func (s *TelegramAdminService) handleViewUser(ctx context.Context, callback model.CallbackQuery, userIDStr string) {
@@ -392,9 +526,20 @@ func (s *TelegramAdminService) handleViewUser(ctx context.Context, callback mode
 		return
 	}
 
+	if _, err := s.userService.GetUserByID(ctx, userID); err != nil {
+		s.answerCallbackQuery(callback.ID, "❌ User not found")
+		return
+	}
+
+	s.showUser(ctx, callback.Message.Chat.ID, callback.Message.MessageID, userID)
+	s.answerCallbackQuery(callback.ID, "")
+}
+
+// showUser shows a user's details with the actions their status allows
+func (s *TelegramAdminService) showUser(ctx context.Context, chatID int64, messageID int, userID uuid.UUID) {
 	user, err := s.userService.GetUserByID(ctx, userID)
 	if err != nil {
-		s.answerCallbackQuery(callback.ID, "❌ User not found")
+		slog.Error("Failed to get user", "userID", userID, "error", err)
 		return
 	}
 
@@ -431,7 +576,14 @@ func (s *TelegramAdminService) handleViewUser(ctx context.Context, callback mode
 	}
 
 	// Add action buttons based on user status
-	if user.ApprovalStatus == "approved" {
+	backTo := "admin_menu:approved"
+	if user.ApprovalStatus == "pending" {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+			{Text: "✅ Approve", CallbackData: fmt.Sprintf("approve_user:%s", user.ID.String())},
+			{Text: "❌ Reject", CallbackData: fmt.Sprintf("reject_user:%s", user.ID.String())},
+		})
+		backTo = "admin_menu:pending"
+	} else if user.ApprovalStatus == "approved" {
 		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
 			{Text: "🚫 Disable", CallbackData: fmt.Sprintf("disable_user:%s", user.ID.String())},
 		})
@@ -442,18 +594,27 @@ func (s *TelegramAdminService) handleViewUser(ctx context.Context, callback mode
 	}
 
 	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
-		{Text: "🔙 Back", CallbackData: "admin_menu:approved"},
+		{Text: "🔙 Back", CallbackData: backTo},
 	})
 
-	s.sendMessageWithKeyboard(callback.Message.Chat.ID, message, keyboard)
-	s.answerCallbackQuery(callback.ID, "")
+	s.render(chatID, messageID, message, keyboard)
 }
 
-func (s *TelegramAdminService) sendMessageWithKeyboard(chatID int64, message string, keyboard model.InlineKeyboardMarkup) {
-	err := s.telegramService.SendMessageWithKeyboard(chatID, message, keyboard)
-	if err != nil {
-		// Fallback to regular message if keyboard fails
-		s.telegramService.SendMessage(chatID, message)
+// render shows an admin view in place of the message a button was pressed on, or as
+// a new message when messageID is 0
+func (s *TelegramAdminService) render(chatID int64, messageID int, message string, keyboard model.InlineKeyboardMarkup) {
+	if err := replyInPlace(s.telegramService, chatID, messageID, message, &keyboard); err != nil {
+		slog.Error("Failed to show admin view", "chatID", chatID, "error", err)
+	}
+}
+
+// closeMessage removes an admin view from the chat. Messages older than 48 hours
+// cannot be deleted, so those just lose their buttons.
+func (s *TelegramAdminService) closeMessage(chatID int64, messageID int) {
+	if err := s.telegramService.DeleteMessage(chatID, messageID); err != nil {
+		if err := s.telegramService.EditMessageKeyboard(chatID, messageID, nil); err != nil {
+			slog.Error("Failed to close admin view", "chatID", chatID, "messageID", messageID, "error", err)
+		}
 	}
 }
 
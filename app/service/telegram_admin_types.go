@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+)
+
+// Notification type management from the admin panel. Every change is shown to the
+// admin first and only made once they press the confirmation button.
+//
+// Callback data:
+//
+//	admin_type:toggle:<id>               ask to activate or deactivate a type
+//	admin_type:active:<id>:<1|0>         activate or deactivate it
+//	admin_type:interval:<id>:<minutes>   set its default interval
+//	admin_type:create                    create the type the admin described
+//	admin_type:cancel                    drop the pending change
+
+// pendingTypeCreates holds the /admin_type_create commands waiting for confirmation.
+// Names do not fit in callback data, so the confirmation button refers to this instead.
+type pendingTypeCreates struct {
+	mutex   sync.Mutex
+	pending map[int64]pendingTypeCreate // admin Telegram user ID -> type to create
+}
+
+type pendingTypeCreate struct {
+	code      string
+	name      string
+	interval  int
+	expiresAt time.Time
+}
+
+// put replaces the admin's pending type
+func (p *pendingTypeCreates) put(adminID int64, create pendingTypeCreate) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.pending == nil {
+		p.pending = make(map[int64]pendingTypeCreate)
+	}
+	create.expiresAt = time.Now().Add(model.CONVERSATION_TIMEOUT)
+	p.pending[adminID] = create
+}
+
+// take removes and returns the admin's pending type, if it has not expired
+func (p *pendingTypeCreates) take(adminID int64) (pendingTypeCreate, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	create, ok := p.pending[adminID]
+	delete(p.pending, adminID)
+	if !ok || time.Now().After(create.expiresAt) {
+		return pendingTypeCreate{}, false
+	}
+	return create, true
+}
+
+var backToTypes = []model.InlineKeyboardButton{
+	{Text: "🔙 Back to Types", CallbackData: "admin_menu:types"},
+}
+
+// showTypes lists every notification type with a button to activate or deactivate it
+func (s *TelegramAdminService) showTypes(ctx context.Context, chatID int64, messageID int, notice string) {
+	types, err := s.notificationTypeService.GetAllTypes(ctx)
+	if err != nil {
+		slog.Error("Failed to get notification types", "error", err)
+		s.render(chatID, messageID, "❌ Failed to get notification types", model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{backToMenu},
+		})
+		return
+	}
+
+	message := ""
+	if notice != "" {
+		message = notice + "\n\n"
+	}
+	message += "🧩 **Notification Types**\n\n"
+
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{},
+	}
+
+	for _, notificationType := range types {
+		status, action := "🟢", "⏸️ Deactivate"
+		if !notificationType.IsActive {
+			status, action = "⚪", "▶️ Activate"
+		}
+		message += fmt.Sprintf("%s `%s` - %s, every %d min\n", status, notificationType.Code, notificationType.Name, notificationType.DefaultIntervalMinutes)
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+			{Text: fmt.Sprintf("%s %s", action, notificationType.Code), CallbackData: fmt.Sprintf("admin_type:toggle:%d", notificationType.ID)},
+		})
+	}
+
+	message += "\nCreate a type with /admin_type_create <code> <minutes> <name>, " +
+		"or change its interval with /admin_type_interval <code> <minutes>."
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, backToMenu)
+
+	s.render(chatID, messageID, message, keyboard)
+}
+
+// askCreateType answers /admin_type_create <code> <minutes> <name...>
+func (s *TelegramAdminService) askCreateType(ctx context.Context, chatID, adminID int64, args []string) {
+	if len(args) < 3 {
+		s.telegramService.SendMessage(chatID, "Usage: /admin_type_create <code> <minutes> <name>\n\nExample: /admin_type_create gold 30 Gold Prices")
+		return
+	}
+
+	code := strings.ToLower(args[0])
+	interval, err := strconv.Atoi(args[1])
+	if err != nil || interval < 1 {
+		s.telegramService.SendMessage(chatID, "❌ The interval must be a whole number of minutes, at least 1.")
+		return
+	}
+	name := strings.Join(args[2:], " ")
+
+	if !typeCodePattern.MatchString(code) {
+		s.telegramService.SendMessage(chatID, "❌ Codes are lowercase letters, digits and underscores, starting with a letter, e.g. gold_price.")
+		return
+	}
+	if _, err := s.notificationTypeService.GetTypeByCode(ctx, code); err == nil {
+		s.telegramService.SendMessage(chatID, fmt.Sprintf("❌ A notification type with code `%s` already exists.", code))
+		return
+	}
+
+	s.pendingCreates.put(adminID, pendingTypeCreate{code: code, name: name, interval: interval})
+
+	message := fmt.Sprintf("🧩 **Create notification type?**\n\nCode: `%s`\nName: %s\nInterval: every %d min\n\n"+
+		"The type starts active. Subscribers need a content provider for it to receive anything.", code, name, interval)
+	s.render(chatID, 0, message, confirmKeyboard("✅ Create", "admin_type:create"))
+}
+
+// askToggleType answers /admin_type_toggle <code> and the list's activate/deactivate buttons
+func (s *TelegramAdminService) askToggleType(chatID int64, messageID int, notificationType *entity.NotificationType) {
+	message := fmt.Sprintf("⏸️ **Deactivate %s?**\n\nSubscribers stop receiving it until it is activated again. Their subscriptions are kept.", notificationType.Name)
+	button, active := "⏸️ Deactivate", 0
+	if !notificationType.IsActive {
+		message = fmt.Sprintf("▶️ **Activate %s?**\n\nUsers can subscribe to it and existing subscribers start receiving it again.", notificationType.Name)
+		button, active = "▶️ Activate", 1
+	}
+
+	s.render(chatID, messageID, message, confirmKeyboard(button, fmt.Sprintf("admin_type:active:%d:%d", notificationType.ID, active)))
+}
+
+// askTypeInterval answers /admin_type_interval <code> <minutes>
+func (s *TelegramAdminService) askTypeInterval(chatID int64, notificationType *entity.NotificationType, interval int) {
+	message := fmt.Sprintf("⏱️ **Change %s interval?**\n\nFrom every %d min to every %d min. Subscribers with their own interval keep it.",
+		notificationType.Name, notificationType.DefaultIntervalMinutes, interval)
+
+	s.render(chatID, 0, message, confirmKeyboard("✅ Change", fmt.Sprintf("admin_type:interval:%d:%d", notificationType.ID, interval)))
+}
+
+// confirmKeyboard is the keyboard of a confirmation prompt
+func confirmKeyboard(confirm, callbackData string) model.InlineKeyboardMarkup {
+	return model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{
+			{
+				{Text: confirm, CallbackData: callbackData},
+				{Text: "✖️ Cancel", CallbackData: "admin_type:cancel"},
+			},
+		},
+	}
+}
+
+// handleTypeCommand handles the /admin_type* commands
+func (s *TelegramAdminService) handleTypeCommand(ctx context.Context, message model.Message, command string, args []string) {
+	chatID := message.Chat.ID
+
+	switch command {
+	case "/admin_types":
+		s.showTypes(ctx, chatID, 0, "")
+		return
+	case "/admin_type_create":
+		s.askCreateType(ctx, chatID, int64(message.From.ID), args)
+		return
+	}
+
+	usage := "Usage: /admin_type_toggle <code>"
+	if command == "/admin_type_interval" {
+		usage = "Usage: /admin_type_interval <code> <minutes>"
+	}
+	if len(args) == 0 {
+		s.telegramService.SendMessage(chatID, usage)
+		return
+	}
+
+	notificationType, err := s.notificationTypeService.GetTypeByCode(ctx, strings.ToLower(args[0]))
+	if err != nil {
+		s.telegramService.SendMessage(chatID, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	if command == "/admin_type_toggle" {
+		s.askToggleType(chatID, 0, notificationType)
+		return
+	}
+
+	if len(args) < 2 {
+		s.telegramService.SendMessage(chatID, usage)
+		return
+	}
+	interval, err := strconv.Atoi(args[1])
+	if err != nil || interval < 1 {
+		s.telegramService.SendMessage(chatID, "❌ The interval must be a whole number of minutes, at least 1.")
+		return
+	}
+	s.askTypeInterval(chatID, notificationType, interval)
+}
+
+// handleTypeCallback handles the admin_type:* buttons
+func (s *TelegramAdminService) handleTypeCallback(ctx context.Context, callback model.CallbackQuery, args []string) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	switch args[0] {
+	case "cancel":
+		s.pendingCreates.take(int64(callback.From.ID))
+		s.answerCallbackQuery(callback.ID, "Cancelled")
+		s.showTypes(ctx, chatID, messageID, "")
+		return
+	case "create":
+		create, ok := s.pendingCreates.take(int64(callback.From.ID))
+		if !ok {
+			s.answerCallbackQuery(callback.ID, "⌛ This request expired, send /admin_type_create again")
+			s.showTypes(ctx, chatID, messageID, "")
+			return
+		}
+
+		notificationType, err := s.notificationTypeService.CreateType(ctx, create.code, create.name, nil, create.interval, entity.PreferenceSchema{})
+		if err != nil {
+			slog.Error("Failed to create notification type", "code", create.code, "error", err)
+			s.answerCallbackQuery(callback.ID, "❌ Failed to create notification type")
+			s.showTypes(ctx, chatID, messageID, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		s.answerCallbackQuery(callback.ID, "✅ Notification type created")
+		s.showTypes(ctx, chatID, messageID, fmt.Sprintf("✅ Created `%s`.", notificationType.Code))
+		return
+	}
+
+	if len(args) < 2 {
+		s.answerCallbackQuery(callback.ID, "❌ Invalid callback data")
+		return
+	}
+
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		s.answerCallbackQuery(callback.ID, "❌ Invalid notification type")
+		return
+	}
+	notificationType, err := s.notificationTypeService.GetTypeByID(ctx, id)
+	if err != nil {
+		s.answerCallbackQuery(callback.ID, "❌ Notification type not found")
+		return
+	}
+
+	switch args[0] {
+	case "toggle":
+		s.answerCallbackQuery(callback.ID, "")
+		s.askToggleType(chatID, messageID, notificationType)
+	case "active":
+		// The button carries the state to set, so pressing it twice changes nothing more
+		if len(args) < 3 {
+			s.answerCallbackQuery(callback.ID, "❌ Invalid callback data")
+			return
+		}
+
+		notice := fmt.Sprintf("⏸️ Deactivated `%s`.", notificationType.Code)
+		if args[2] == "1" {
+			err = s.notificationTypeService.ActivateType(ctx, notificationType.Code)
+			notice = fmt.Sprintf("▶️ Activated `%s`.", notificationType.Code)
+		} else {
+			err = s.notificationTypeService.DeactivateType(ctx, notificationType.Code)
+		}
+		if err != nil {
+			slog.Error("Failed to change notification type status", "code", notificationType.Code, "error", err)
+			s.answerCallbackQuery(callback.ID, "❌ Failed to update notification type")
+			return
+		}
+		s.answerCallbackQuery(callback.ID, "✅ Saved")
+		s.showTypes(ctx, chatID, messageID, notice)
+	case "interval":
+		if len(args) < 3 {
+			s.answerCallbackQuery(callback.ID, "❌ Invalid callback data")
+			return
+		}
+
+		interval, err := strconv.Atoi(args[2])
+		if err != nil {
+			s.answerCallbackQuery(callback.ID, "❌ Invalid interval")
+			return
+		}
+
+		notificationType.DefaultIntervalMinutes = interval
+		if err := s.notificationTypeService.UpdateType(ctx, notificationType); err != nil {
+			slog.Error("Failed to change notification type interval", "code", notificationType.Code, "error", err)
+			message := "❌ Failed to update notification type"
+			if errors.Is(err, ErrInvalidTypeInterval) {
+				message = "❌ Invalid interval"
+			}
+			s.answerCallbackQuery(callback.ID, message)
+			return
+		}
+		s.answerCallbackQuery(callback.ID, "✅ Saved")
+		s.showTypes(ctx, chatID, messageID, fmt.Sprintf("⏱️ `%s` now runs every %d min.", notificationType.Code, interval))
+	default:
+		s.answerCallbackQuery(callback.ID, "❌ Unknown action")
+	}
+}
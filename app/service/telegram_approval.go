@@ -0,0 +1,15 @@
+package service
+
+// approvalStatusMessage explains to a user why their account cannot use the bot yet
+func approvalStatusMessage(status string) string {
+	switch status {
+	case "pending":
+		return "⏳ Your account is waiting for admin approval.\n\nYou can browse notification types with /types, and you'll be able to subscribe once an admin approves you."
+	case "rejected":
+		return "❌ Your registration was not approved, so you can't subscribe to notifications."
+	case "disabled":
+		return "🚫 Your account has been disabled by an admin. Your subscriptions are paused until it is enabled again."
+	default:
+		return "❌ Your account is not approved to use notifications."
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -19,12 +20,16 @@ import (
 type TelegramBotService struct {
 	botInstance             *bot.Bot
 	rateLimiter             *model.RateLimiter
+	outboundLimiter         *model.OutboundLimiter
+	conversations           *model.ConversationStore
 	messageValidator        *model.MessageValidator
 	userService             UserService
 	subscriptionService     SubscriptionService
 	notificationTypeService NotificationTypeService
 	adminService            AdminServiceInterface
 	telegramAdminService    *TelegramAdminService
+	channelService          ChannelService
+	contentProviders        *ContentProviderRegistry
 }
 
 // TelegramBotServiceInterface defines the interface for telegram bot operations
@@ -34,37 +39,55 @@ type TelegramBotServiceInterface interface {
 	HandleUpdate(ctx context.Context, b *bot.Bot, update *models.Update)
 }
 
-// NewTelegramBotService creates a new telegram bot service with all dependencies
+// NewTelegramBotService creates a new telegram bot service with all dependencies.
+// outboundRatePerSecond caps everything the bot sends; zero uses Telegram's limit.
+// Options are passed on to the bot client, e.g. to point it at another API server.
 func NewTelegramBotService(
 	botToken string,
 	userService UserService,
 	subscriptionService SubscriptionService,
 	notificationTypeService NotificationTypeService,
 	adminService AdminServiceInterface,
+	contentProviders *ContentProviderRegistry,
+	outboundRatePerSecond int,
+	options ...bot.Option,
 ) *TelegramBotService {
 	if botToken == "" {
 		panic("TELEGRAM BOT TOKEN environment variable not set.")
 	}
 
-	botInstance, err := bot.New(botToken)
+	botInstance, err := bot.New(botToken, options...)
 	if err != nil {
 		log.Fatalf("Failed to create bot: %v", err)
 	}
 
+	if outboundRatePerSecond <= 0 {
+		outboundRatePerSecond = model.OUTBOUND_GLOBAL_PER_SECOND
+	}
+
 	service := &TelegramBotService{
 		botInstance:             botInstance,
 		rateLimiter:             model.NewRateLimiter(),
+		outboundLimiter:         model.NewOutboundLimiterWithLimits(outboundRatePerSecond, model.OUTBOUND_CHAT_INTERVAL, model.OUTBOUND_GROUP_INTERVAL),
+		conversations:           model.NewConversationStore(model.CONVERSATION_TIMEOUT),
 		messageValidator:        model.NewMessageValidator(),
 		userService:             userService,
 		subscriptionService:     subscriptionService,
 		notificationTypeService: notificationTypeService,
 		adminService:            adminService,
+		contentProviders:        contentProviders,
 	}
 
+	// Channel subscriptions are resolved and permission-checked through this bot
+	service.channelService = NewChannelService(service, userService, subscriptionService)
+
 	// Initialize telegram admin service
 	if userService != nil && adminService != nil {
 		// Use the TelegramBotService itself as it implements TelegramNotificationSender
-		service.telegramAdminService = NewTelegramAdminService(service, adminService, userService)
+		service.telegramAdminService = NewTelegramAdminService(service, adminService, userService, notificationTypeService)
+
+		// Admins hear about new users waiting for approval through the bot
+		userService.SetRegistrationNotifier(service.telegramAdminService)
 	}
 
 	return service
@@ -72,8 +95,6 @@ func NewTelegramBotService(
 
 // SendMessage sends a message to a specific chat
 func (ts *TelegramBotService) SendMessage(chatID int64, message string) error {
-	// Note: Rate limiting is applied to incoming messages, not outgoing bot responses
-
 	// Validate message
 	if err := model.ValidateMessageString(message); err != nil {
 		return fmt.Errorf("message validation failed: %w", err)
@@ -82,13 +103,46 @@ func (ts *TelegramBotService) SendMessage(chatID int64, message string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	_, err := ts.botInstance.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID: chatID,
-		Text:   message,
-		// Remove ParseMode to send as plain text to avoid markdown parsing issues
+	return ts.sendLimited(ctx, chatID, func(ctx context.Context) error {
+		_, err := ts.botInstance.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   message,
+			// Remove ParseMode to send as plain text to avoid markdown parsing issues
+		})
+		return err
 	})
+}
 
-	return err
+// sendLimited runs send once the outbound limiter allows it. A chat Telegram has
+// already paused fails fast with a ChatPausedError, so callers such as the outbox can
+// reschedule instead of blocking. When Telegram answers 429 the chat is paused for
+// retry_after and the send is retried, as long as the pause fits in the context
+// deadline; otherwise the 429 is returned to the caller.
+func (ts *TelegramBotService) sendLimited(ctx context.Context, chatID int64, send func(ctx context.Context) error) error {
+	if until := ts.outboundLimiter.PausedUntil(chatID); time.Now().Before(until) {
+		return &model.ChatPausedError{ChatID: chatID, Until: until}
+	}
+
+	for {
+		if err := ts.outboundLimiter.Wait(ctx, chatID); err != nil {
+			return fmt.Errorf("waiting for outbound rate limit: %w", err)
+		}
+
+		err := send(ctx)
+
+		var tooMany *bot.TooManyRequestsError
+		if !errors.As(err, &tooMany) {
+			return err
+		}
+
+		retryAfter := time.Duration(tooMany.RetryAfter) * time.Second
+		ts.outboundLimiter.Pause(chatID, retryAfter)
+		log.Printf("⏳ Telegram rate limit for chat %d, pausing for %s", chatID, retryAfter)
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < retryAfter {
+			return err
+		}
+	}
 }
 
 // StartPolling starts the bot polling loop
@@ -188,25 +242,44 @@ func (ts *TelegramBotService) handleCommand(ctx context.Context, chatID, userID
 		return
 	}
 
-	cmd := strings.ToLower(parts[0])
+	// In groups commands may be addressed to a bot, e.g. /subscribe@MyBot
+	cmd, _, _ := strings.Cut(strings.ToLower(parts[0]), "@")
 	slog.Debug("Received command", "command", cmd, "chatID", chatID, "userID", userID)
 
+	// The admin service checks permissions and handles the /admin_* commands itself
+	if strings.HasPrefix(cmd, "/admin_") && ts.telegramAdminService != nil {
+		ts.telegramAdminService.HandleAdminCommand(ctx, model.Message{
+			From: model.User{ID: int(userID)},
+			Chat: model.Chat{ID: chatID},
+			Text: strings.Join(append([]string{cmd}, parts[1:]...), " "),
+		})
+		return
+	}
+
 	switch cmd {
 	case "/start":
 		ts.handleStartCommand(ctx, chatID, userID)
 	case "/help":
-		ts.handleHelpCommand(ctx, chatID, userID)
+		ts.handleHelpCommand(ctx, chatID, userID, 0)
 	case "/subscribe":
-		ts.handleSubscribeCommand(ctx, chatID, userID, parts)
+		ts.handleSubscribeCommand(ctx, chatID, userID, 0, parts)
 	case "/unsubscribe":
-		ts.handleUnsubscribeCommand(ctx, chatID, userID, parts)
+		ts.handleUnsubscribeCommand(ctx, chatID, userID, 0, parts)
 	case "/list":
-		ts.handleListCommand(ctx, chatID, userID)
+		ts.handleListCommand(ctx, chatID, userID, 0)
 	case "/types":
-		ts.handleTypesCommand(ctx, chatID, userID)
+		ts.handleTypesCommand(ctx, chatID, userID, 0)
+	case "/settings":
+		ts.handleSettingsCommand(ctx, chatID, userID, parts)
+	case "/cancel":
+		ts.handleCancelCommand(ctx, chatID, userID)
+	case "/timezone":
+		ts.handleTimezoneCommand(ctx, chatID, userID, parts)
+	case "/channel":
+		ts.handleChannelCommand(ctx, chatID, userID, parts)
 	case "/admin":
 		slog.Info("[DEBUG] /admin command detected in TelegramBotService", "userID", userID, "chatID", chatID)
-		ts.handleAdminCommand(ctx, chatID, userID, command)
+		ts.handleAdminCommand(ctx, chatID, userID, 0, command)
 	default:
 		ts.SendMessage(chatID, "❓ Unknown command. Type /help to see available commands.")
 	}
@@ -229,6 +302,7 @@ Available Commands:
 • /subscribe <type> - Subscribe to notifications
 • /unsubscribe <type> - Unsubscribe from notifications
 • /list - Show your subscriptions
+• /settings <type> - Change a subscription's settings
 • /help - Show help menu
 
 Examples:
@@ -251,10 +325,16 @@ Examples:
 
 	// Check if user is admin and add admin button
 	if ts.userService != nil {
-		if user, err := ts.userService.GetUserByTelegramID(ctx, userID); err == nil && user.Role == "admin" {
-			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
-				{Text: "🔧 Admin Panel", CallbackData: "admin:main"},
-			})
+		if user, err := ts.userService.GetUserByTelegramID(ctx, userID); err == nil {
+			if user.Role == "admin" {
+				keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+					{Text: "🔧 Admin Panel", CallbackData: "admin:main"},
+				})
+			}
+			// Let users who cannot subscribe yet know why
+			if user.ApprovalStatus != "approved" {
+				message += "\n\n" + approvalStatusMessage(user.ApprovalStatus)
+			}
 		}
 	}
 
@@ -267,7 +347,7 @@ Examples:
 }
 
 // handleHelpCommand handles the /help command
-func (ts *TelegramBotService) handleHelpCommand(ctx context.Context, chatID, userID int64) {
+func (ts *TelegramBotService) handleHelpCommand(ctx context.Context, chatID, userID int64, messageID int) {
 	message := `📚 Help & Support
 
 Getting Started:
@@ -284,9 +364,13 @@ Subscription Management:
 • /subscribe <type> - Subscribe to notifications
 • /unsubscribe <type> - Unsubscribe from notifications
 • /list - Show your current subscriptions
+• /settings <type> - Change currency, threshold, interval and more
+• /cancel - Stop the current settings dialogue
+• /timezone <Area/City> - Set your time zone for schedules
 
 Examples:
 • /subscribe coinbase - Get crypto updates
+• /subscribe price_alert currency=ETH threshold=3500 - Alert on a price
 • /subscribe news - Get news notifications
 • /subscribe weather - Get weather updates
 • /unsubscribe coinbase - Stop crypto notifications`
@@ -307,7 +391,11 @@ Examples:
 			message += `
 
 🔧 Admin Commands:
-• /admin - Access admin panel for user management`
+• /admin - Access admin panel for user management
+• /admin_pending, /admin_approved - Page through users by status
+• /admin_find <username|id> - Look up a user
+• /admin_types - Manage notification types
+• /channel - Post notifications into a Telegram channel`
 
 			// Add admin button
 			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
@@ -316,15 +404,15 @@ Examples:
 		}
 	}
 
-	ts.SendMessageWithKeyboard(chatID, message, keyboard)
+	ts.reply(chatID, messageID, message, &keyboard)
 }
 
 // handleSubscribeCommand handles the /subscribe command
-func (ts *TelegramBotService) handleSubscribeCommand(ctx context.Context, chatID, userID int64, parts []string) {
+func (ts *TelegramBotService) handleSubscribeCommand(ctx context.Context, chatID, userID int64, messageID int, parts []string) {
 	if len(parts) < 2 {
 		message := `❓ How to Subscribe
 
-Usage: /subscribe <notification_type>
+Usage: /subscribe <notification_type> [key=value ...]
 
 Available types:
 • coinbase - Cryptocurrency price updates
@@ -333,11 +421,18 @@ Available types:
 • price_alert - Custom price alerts (requires currency and threshold)
 • custom - Custom notifications
 
-Example: /subscribe coinbase
+Examples:
+• /subscribe coinbase
+• /subscribe price_alert currency=ETH threshold=3500 interval=10
+• /subscribe news keywords=golang,kubernetes
 
-For price alerts, you'll need to provide additional preferences after subscribing.
+Settings can be changed later with /settings <type>.
 Type /types for more details about each type.`
-		ts.SendMessage(chatID, message)
+		ts.replyText(chatID, messageID, message)
+		return
+	}
+
+	if !ts.requireChatAdmin(ctx, chatID, userID) {
 		return
 	}
 
@@ -346,44 +441,73 @@ Type /types for more details about each type.`
 	// Check if notification type exists
 	notificationTypeEntity, err := ts.notificationTypeService.GetTypeByCode(ctx, notificationType)
 	if err != nil {
-		ts.SendMessage(chatID, fmt.Sprintf("❌ Unknown notification type '%s'. Type /types to see available options.", notificationType))
+		ts.replyText(chatID, messageID, fmt.Sprintf("❌ Unknown notification type '%s'. Type /types to see available options.", notificationType))
 		return
 	}
 
-	// Handle special cases for notification types that require preferences
+	// Apply inline key=value settings, e.g. /subscribe price_alert currency=ETH threshold=3500.
+	// Anything left out falls back to the defaults in the type's preference schema.
 	var preferences *entity.SubscriptionPreferences
-	if notificationType == "price_alert" {
-		// For now, set default preferences that will work
-		preferences = &entity.SubscriptionPreferences{
-			Currency:  "BTC",
-			Threshold: 50000.0,
-			Interval:  5, // 5 minutes
+	if len(parts) > 2 {
+		preferences = &entity.SubscriptionPreferences{}
+		if err := ParsePreferenceArgs(notificationTypeEntity, parts[2:], preferences); err != nil {
+			ts.replyText(chatID, messageID, fmt.Sprintf("❌ %v\n\nUsage: /subscribe %s key=value ...\nValid settings: %s", err, notificationType, strings.Join(preferenceKeys(notificationTypeEntity), ", ")))
+			return
 		}
-
-		ts.SendMessage(chatID, "⚠️ Price alerts require specific settings. I've set default values for you:\n• Currency: BTC\n• Threshold: $50,000\n• Check interval: 5 minutes\n\nYou can modify these later if needed.")
 	}
 
 	// Subscribe user
 	subscription, err := ts.subscriptionService.Subscribe(ctx, userID, chatID, notificationType, preferences)
 	if err != nil {
 		log.Printf("Failed to subscribe user %d to %s: %v", userID, notificationType, err)
-		ts.SendMessage(chatID, "❌ Failed to subscribe. Please try again later.")
+		var preferenceErr *PreferenceError
+		if errors.As(err, &preferenceErr) {
+			ts.replyText(chatID, messageID, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		var notApproved *UserNotApprovedError
+		if errors.As(err, &notApproved) {
+			ts.replyText(chatID, messageID, approvalStatusMessage(notApproved.Status))
+			return
+		}
+		ts.replyText(chatID, messageID, "❌ Failed to subscribe. Please try again later.")
 		return
 	}
 
-	var successMessage string
-	if notificationType == "price_alert" {
-		successMessage = fmt.Sprintf("✅ Successfully subscribed to %s notifications!\n\nDefault settings:\n• Currency: BTC\n• Threshold: $50,000\n• Interval: 5 minutes\n\nType /list to see all your subscriptions.", notificationTypeEntity.Name)
+	var successMessage strings.Builder
+	successMessage.WriteString(fmt.Sprintf("✅ Successfully subscribed to %s notifications!\n\n", notificationTypeEntity.Name))
+
+	var settings []string
+	for _, field := range PreferenceFields(notificationTypeEntity) {
+		if value := field.Get(&subscription.Preferences); value != "" {
+			settings = append(settings, fmt.Sprintf("• %s: %s\n", field.Label, value))
+		}
+	}
+	if len(settings) > 0 {
+		successMessage.WriteString("Settings:\n")
+		successMessage.WriteString(strings.Join(settings, ""))
+		successMessage.WriteString(fmt.Sprintf("\nType /settings %s to change them.", notificationType))
 	} else {
-		successMessage = fmt.Sprintf("✅ Successfully subscribed to %s notifications!\n\nYou'll receive updates based on the default interval. Type /list to see all your subscriptions.", notificationTypeEntity.Name)
+		successMessage.WriteString("You'll receive updates based on the default interval. Type /list to see all your subscriptions.")
 	}
 
-	ts.SendMessage(chatID, successMessage)
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{
+			{
+				{Text: "⚙️ Settings", CallbackData: fmt.Sprintf("settings:%s", notificationType)},
+				{Text: "📱 My Subscriptions", CallbackData: "list:mine"},
+			},
+			{
+				{Text: "📋 Browse Types", CallbackData: "types:all"},
+			},
+		},
+	}
+	ts.reply(chatID, messageID, successMessage.String(), &keyboard)
 	log.Printf("User %d subscribed to %s (subscription ID: %d)", userID, notificationType, subscription.ID)
 }
 
 // handleUnsubscribeCommand handles the /unsubscribe command
-func (ts *TelegramBotService) handleUnsubscribeCommand(ctx context.Context, chatID, userID int64, parts []string) {
+func (ts *TelegramBotService) handleUnsubscribeCommand(ctx context.Context, chatID, userID int64, messageID int, parts []string) {
 	if len(parts) < 2 {
 		message := `❓ How to Unsubscribe
 
@@ -392,30 +516,62 @@ Usage: /unsubscribe <notification_type>
 Example: /unsubscribe coinbase
 
 Type /list to see your current subscriptions.`
-		ts.SendMessage(chatID, message)
+		ts.replyText(chatID, messageID, message)
+		return
+	}
+
+	if !ts.requireChatAdmin(ctx, chatID, userID) {
 		return
 	}
 
 	notificationType := strings.ToLower(parts[1])
 
-	// Unsubscribe user
-	err := ts.subscriptionService.Unsubscribe(ctx, userID, notificationType)
+	// Unsubscribe the chat
+	err := ts.subscriptionService.Unsubscribe(ctx, userID, chatID, notificationType)
 	if err != nil {
 		log.Printf("Failed to unsubscribe user %d from %s: %v", userID, notificationType, err)
-		ts.SendMessage(chatID, "❌ Failed to unsubscribe. You might not be subscribed to this type.")
+		ts.replyText(chatID, messageID, "❌ Failed to unsubscribe. You might not be subscribed to this type.")
 		return
 	}
 
-	ts.SendMessage(chatID, fmt.Sprintf("✅ Successfully unsubscribed from %s notifications.", notificationType))
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{
+			{
+				{Text: "📱 My Subscriptions", CallbackData: "list:mine"},
+				{Text: "📋 Browse Types", CallbackData: "types:all"},
+			},
+		},
+	}
+	ts.reply(chatID, messageID, fmt.Sprintf("✅ Successfully unsubscribed from %s notifications.", notificationType), &keyboard)
 	log.Printf("User %d unsubscribed from %s", userID, notificationType)
 }
 
+// handleTimezoneCommand shows or sets the time zone used for schedules and quiet hours
+func (ts *TelegramBotService) handleTimezoneCommand(ctx context.Context, chatID, userID int64, parts []string) {
+	if len(parts) < 2 {
+		current := "UTC"
+		if user, err := ts.userService.GetUserByTelegramID(ctx, userID); err == nil && user.Timezone != "" {
+			current = user.Timezone
+		}
+		ts.SendMessage(chatID, fmt.Sprintf("🌍 Your time zone is %s\n\nUsage: /timezone <Area/City>\nExample: /timezone Europe/Berlin\n\nSchedules and quiet hours use this time zone.", current))
+		return
+	}
+
+	if err := ts.userService.UpdateTimezone(ctx, userID, parts[1]); err != nil {
+		log.Printf("Failed to update time zone for user %d: %v", userID, err)
+		ts.SendMessage(chatID, fmt.Sprintf("❌ Could not set time zone: %v", err))
+		return
+	}
+
+	ts.SendMessage(chatID, fmt.Sprintf("✅ Time zone set to %s", parts[1]))
+}
+
 // handleListCommand handles the /list command
-func (ts *TelegramBotService) handleListCommand(ctx context.Context, chatID, userID int64) {
-	subscriptions, err := ts.subscriptionService.GetUserSubscriptions(ctx, userID)
+func (ts *TelegramBotService) handleListCommand(ctx context.Context, chatID, userID int64, messageID int) {
+	subscriptions, err := ts.subscriptionService.GetChatSubscriptions(ctx, chatID)
 	if err != nil {
-		log.Printf("Failed to get subscriptions for user %d: %v", userID, err)
-		ts.SendMessage(chatID, "❌ Failed to retrieve your subscriptions.")
+		log.Printf("Failed to get subscriptions for chat %d: %v", chatID, err)
+		ts.replyText(chatID, messageID, "❌ Failed to retrieve your subscriptions.")
 		return
 	}
 
@@ -436,12 +592,16 @@ Use the buttons below to get started!`
 				},
 			},
 		}
-		ts.SendMessageWithKeyboard(chatID, message, keyboard)
+		ts.reply(chatID, messageID, message, &keyboard)
 		return
 	}
 
 	var message strings.Builder
-	message.WriteString(fmt.Sprintf("📝 Your Active Subscriptions (%d):\n\n", len(subscriptions)))
+	if isPrivateChat(chatID, userID) {
+		message.WriteString(fmt.Sprintf("📝 Your Active Subscriptions (%d):\n\n", len(subscriptions)))
+	} else {
+		message.WriteString(fmt.Sprintf("📝 This Group's Active Subscriptions (%d):\n\n", len(subscriptions)))
+	}
 
 	// Create keyboard with unsubscribe buttons
 	keyboard := model.InlineKeyboardMarkup{
@@ -456,15 +616,24 @@ Use the buttons below to get started!`
 				interval = fmt.Sprintf("%d min", sub.Preferences.Interval)
 			}
 
+			if sub.Preferences.Schedule != "" {
+				interval = fmt.Sprintf("schedule %s", sub.Preferences.Schedule)
+			}
+
 			message.WriteString(fmt.Sprintf("%s %s - %s\n", status, sub.NotificationType.Name, interval))
 
+			if sub.Preferences.QuietHours != "" {
+				message.WriteString(fmt.Sprintf("   🌙 Quiet hours: %s\n", sub.Preferences.QuietHours))
+			}
+
 			if sub.LastNotifiedAt != nil {
 				message.WriteString(fmt.Sprintf("   📅 Last update: %s\n", sub.LastNotifiedAt.Format("Jan 2, 15:04")))
 			}
 			message.WriteString("\n")
 
-			// Add unsubscribe button for each active subscription
+			// Add settings and unsubscribe buttons for each active subscription
 			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+				{Text: "⚙️ Settings", CallbackData: fmt.Sprintf("settings:%s", sub.NotificationType.Code)},
 				{Text: fmt.Sprintf("❌ Unsubscribe from %s", sub.NotificationType.Name), CallbackData: fmt.Sprintf("unsubscribe:%s", sub.NotificationType.Code)},
 			})
 		}
@@ -478,15 +647,15 @@ Use the buttons below to get started!`
 		{Text: "❓ Help", CallbackData: "help:main"},
 	})
 
-	ts.SendMessageWithKeyboard(chatID, message.String(), keyboard)
+	ts.reply(chatID, messageID, message.String(), &keyboard)
 }
 
 // handleTypesCommand handles the /types command
-func (ts *TelegramBotService) handleTypesCommand(ctx context.Context, chatID, userID int64) {
+func (ts *TelegramBotService) handleTypesCommand(ctx context.Context, chatID, userID int64, messageID int) {
 	types, err := ts.notificationTypeService.GetActiveTypes(ctx)
 	if err != nil {
 		log.Printf("Failed to get notification types: %v", err)
-		ts.SendMessage(chatID, "❌ Failed to retrieve notification types.")
+		ts.replyText(chatID, messageID, "❌ Failed to retrieve notification types.")
 		return
 	}
 
@@ -503,7 +672,21 @@ func (ts *TelegramBotService) handleTypesCommand(ctx context.Context, chatID, us
 		if nt.Description != nil {
 			message.WriteString(fmt.Sprintf("   %s\n", *nt.Description))
 		}
-		message.WriteString(fmt.Sprintf("   📊 Default interval: %d minutes\n\n", nt.DefaultIntervalMinutes))
+		message.WriteString(fmt.Sprintf("   📊 Default interval: %d minutes\n", nt.DefaultIntervalMinutes))
+		if settings := DescribePreferenceSchema(nt.PreferenceSchema); len(settings) > 0 {
+			message.WriteString("   ⚙️ Settings:\n")
+			for _, setting := range settings {
+				message.WriteString(fmt.Sprintf("      • %s\n", setting))
+			}
+		}
+
+		// Types without a content provider cannot deliver anything yet, unless
+		// another service sends them
+		if ts.contentProviders != nil && !ts.contentProviders.Delivers(nt.Code) {
+			message.WriteString("   ⚠️ Not available yet (no content provider)\n\n")
+			continue
+		}
+		message.WriteString("\n")
 
 		// Add subscribe button for each type
 		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
@@ -520,12 +703,22 @@ func (ts *TelegramBotService) handleTypesCommand(ctx context.Context, chatID, us
 		{Text: "❓ Help", CallbackData: "help:main"},
 	})
 
-	ts.SendMessageWithKeyboard(chatID, message.String(), keyboard)
+	ts.reply(chatID, messageID, message.String(), &keyboard)
 }
 
 // handleMessage processes regular (non-command) messages
 func (ts *TelegramBotService) handleMessage(ctx context.Context, chatID, userID int64, text string) {
-	// For now, just acknowledge the message
+	// Replies to a pending /settings prompt
+	if ts.handleConversationReply(ctx, chatID, userID, text) {
+		return
+	}
+
+	// Groups are full of messages not meant for the bot
+	if !isPrivateChat(chatID, userID) {
+		return
+	}
+
+	// Otherwise just acknowledge the message
 	responses := []string{
 		"Thanks for your message! Use /help to see what I can do.",
 		"I received your message. Type /help for available commands.",
@@ -538,6 +731,72 @@ func (ts *TelegramBotService) handleMessage(ctx context.Context, chatID, userID
 
 // SendMessageWithKeyboard sends a message with an inline keyboard
 func (ts *TelegramBotService) SendMessageWithKeyboard(chatID int64, message string, keyboard model.InlineKeyboardMarkup) error {
+	_, err := ts.SendMessageWithKeyboardID(chatID, message, keyboard)
+	return err
+}
+
+// SendMessageWithKeyboardID sends a message with an inline keyboard and returns its
+// message ID, so the message can be edited later
+func (ts *TelegramBotService) SendMessageWithKeyboardID(chatID int64, message string, keyboard model.InlineKeyboardMarkup) (int, error) {
+	// Validate message
+	if err := model.ValidateMessageString(message); err != nil {
+		return 0, fmt.Errorf("message validation failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var messageID int
+	err := ts.sendLimited(ctx, chatID, func(ctx context.Context) error {
+		sent, err := ts.botInstance.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      chatID,
+			Text:        message,
+			ReplyMarkup: toBotKeyboard(keyboard),
+		})
+		if err != nil {
+			return err
+		}
+		messageID = sent.ID
+		return nil
+	})
+	return messageID, err
+}
+
+// SendFormattedMessage sends a message in a parse mode (empty for plain text) with
+// optional inline buttons and returns its message ID
+func (ts *TelegramBotService) SendFormattedMessage(chatID int64, message, parseMode string, keyboard *model.InlineKeyboardMarkup) (int, error) {
+	// Validate message
+	if err := model.ValidateMessageString(message); err != nil {
+		return 0, fmt.Errorf("message validation failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	params := &bot.SendMessageParams{
+		ChatID:    chatID,
+		Text:      message,
+		ParseMode: models.ParseMode(parseMode),
+	}
+	if keyboard != nil {
+		params.ReplyMarkup = toBotKeyboard(*keyboard)
+	}
+
+	var messageID int
+	err := ts.sendLimited(ctx, chatID, func(ctx context.Context) error {
+		sent, err := ts.botInstance.SendMessage(ctx, params)
+		if err != nil {
+			return err
+		}
+		messageID = sent.ID
+		return nil
+	})
+	return messageID, err
+}
+
+// EditMessageWithKeyboard replaces the text and inline keyboard of a message the bot
+// sent. A nil keyboard removes the buttons.
+func (ts *TelegramBotService) EditMessageWithKeyboard(chatID int64, messageID int, message string, keyboard *model.InlineKeyboardMarkup) error {
 	// Validate message
 	if err := model.ValidateMessageString(message); err != nil {
 		return fmt.Errorf("message validation failed: %w", err)
@@ -546,7 +805,91 @@ func (ts *TelegramBotService) SendMessageWithKeyboard(chatID int64, message stri
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Convert model.InlineKeyboardMarkup to bot package format
+	params := &bot.EditMessageTextParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Text:      message,
+	}
+	if keyboard != nil {
+		params.ReplyMarkup = toBotKeyboard(*keyboard)
+	}
+
+	return ts.sendLimited(ctx, chatID, func(ctx context.Context) error {
+		_, err := ts.botInstance.EditMessageText(ctx, params)
+		return err
+	})
+}
+
+// EditMessageKeyboard replaces the inline keyboard of a message the bot sent. A nil
+// keyboard removes the buttons.
+func (ts *TelegramBotService) EditMessageKeyboard(chatID int64, messageID int, keyboard *model.InlineKeyboardMarkup) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	params := &bot.EditMessageReplyMarkupParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+	}
+	if keyboard != nil {
+		params.ReplyMarkup = toBotKeyboard(*keyboard)
+	}
+
+	return ts.sendLimited(ctx, chatID, func(ctx context.Context) error {
+		_, err := ts.botInstance.EditMessageReplyMarkup(ctx, params)
+		return err
+	})
+}
+
+// DeleteMessage deletes a message from a chat
+func (ts *TelegramBotService) DeleteMessage(chatID int64, messageID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return ts.sendLimited(ctx, chatID, func(ctx context.Context) error {
+		_, err := ts.botInstance.DeleteMessage(ctx, &bot.DeleteMessageParams{
+			ChatID:    chatID,
+			MessageID: messageID,
+		})
+		return err
+	})
+}
+
+// replyText is reply without buttons
+func (ts *TelegramBotService) replyText(chatID int64, messageID int, message string) error {
+	return ts.reply(chatID, messageID, message, nil)
+}
+
+// reply answers in place: a button press (messageID set) edits the message the button
+// is on, anything else sends a new message
+func (ts *TelegramBotService) reply(chatID int64, messageID int, message string, keyboard *model.InlineKeyboardMarkup) error {
+	return replyInPlace(ts, chatID, messageID, message, keyboard)
+}
+
+// replyInPlace edits messageID when it is set and falls back to sending a new message
+// when the edit fails, e.g. because the message is too old to edit
+func replyInPlace(sender TelegramNotificationSender, chatID int64, messageID int, message string, keyboard *model.InlineKeyboardMarkup) error {
+	if messageID != 0 {
+		err := sender.EditMessageWithKeyboard(chatID, messageID, message, keyboard)
+		if err == nil || isMessageNotModified(err) {
+			return nil
+		}
+		slog.Warn("Failed to edit message, sending a new one", "chatID", chatID, "messageID", messageID, "error", err)
+	}
+
+	if keyboard == nil {
+		return sender.SendMessage(chatID, message)
+	}
+	return sender.SendMessageWithKeyboard(chatID, message, *keyboard)
+}
+
+// isMessageNotModified reports whether Telegram refused an edit that changes nothing,
+// which happens when the same button is pressed twice
+func isMessageNotModified(err error) bool {
+	return errors.Is(err, bot.ErrorBadRequest) && strings.Contains(err.Error(), "message is not modified")
+}
+
+// toBotKeyboard converts model.InlineKeyboardMarkup to bot package format
+func toBotKeyboard(keyboard model.InlineKeyboardMarkup) *models.InlineKeyboardMarkup {
 	var botKeyboard [][]models.InlineKeyboardButton
 	for _, row := range keyboard.InlineKeyboard {
 		var botRow []models.InlineKeyboardButton
@@ -565,17 +908,9 @@ func (ts *TelegramBotService) SendMessageWithKeyboard(chatID int64, message stri
 		botKeyboard = append(botKeyboard, botRow)
 	}
 
-	replyMarkup := &models.InlineKeyboardMarkup{
+	return &models.InlineKeyboardMarkup{
 		InlineKeyboard: botKeyboard,
 	}
-
-	_, err := ts.botInstance.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: replyMarkup,
-	})
-
-	return err
 }
 
 // AnswerCallbackQuery answers a callback query (public interface method)
@@ -602,29 +937,29 @@ func getDisplayName(user *entity.User) string {
 }
 
 // handleAdminCommand handles admin commands with proper role checking
-func (ts *TelegramBotService) handleAdminCommand(ctx context.Context, chatID, userID int64, command string) {
+func (ts *TelegramBotService) handleAdminCommand(ctx context.Context, chatID, userID int64, messageID int, command string) {
 	slog.Info("[DEBUG] Admin command received in TelegramBotService", "command", command, "userID", userID)
 
 	if ts.userService == nil {
-		ts.SendMessage(chatID, "❌ User service is not available")
+		ts.replyText(chatID, messageID, "❌ User service is not available")
 		return
 	}
 
 	// Check if user exists and has admin role
 	user, err := ts.userService.GetUserByTelegramID(ctx, userID)
 	if err != nil {
-		ts.SendMessage(chatID, "❌ You need to register first. Use /start command.")
+		ts.replyText(chatID, messageID, "❌ You need to register first. Use /start command.")
 		return
 	}
 
 	if user.Role != "admin" {
-		ts.SendMessage(chatID, "❌ You don't have admin permissions.")
+		ts.replyText(chatID, messageID, "❌ You don't have admin permissions.")
 		slog.Info("Non-admin user attempted admin command", "userID", userID, "role", user.Role)
 		return
 	}
 
 	// Show admin panel with buttons
-	ts.showAdminPanel(ctx, chatID, userID)
+	ts.showAdminPanel(ctx, chatID, userID, messageID)
 }
 
 // Debug method to check if services are properly initialized
@@ -640,50 +975,89 @@ func (ts *TelegramBotService) CheckAdminServices() {
 func (ts *TelegramBotService) handleCallbackQuery(ctx context.Context, callbackQuery *models.CallbackQuery) {
 	log.Printf("Received callback query: %s from user %d", callbackQuery.Data, callbackQuery.From.ID)
 
-	// Answer the callback query first
-	ts.answerCallbackQuery(ctx, callbackQuery.ID, "")
-
 	// Parse callback data
 	data := callbackQuery.Data
 	parts := strings.Split(data, ":")
 
 	if len(parts) < 2 {
 		log.Printf("Invalid callback data format: %s", data)
+		ts.answerCallbackQuery(ctx, callbackQuery.ID, "")
 		return
 	}
 
 	action := parts[0]
 	param := parts[1]
 
-	// Extract chat ID - for callback queries, we need to get it from the original message
-	// For now, let's try to extract it from the From ID (assuming private chat)
-	chatID := callbackQuery.From.ID
+	// User management buttons belong to the admin service, which answers them itself
+	if ts.telegramAdminService != nil && (adminCallbackActions[action] || action == "admin" && param != "main") {
+		callback := toAdminCallback(callbackQuery)
+		if action == "admin" {
+			// The admin panel's buttons open the admin service's views
+			callback.Data = "admin_menu:" + param
+		}
+		ts.telegramAdminService.HandleCallbackQuery(ctx, callback)
+		return
+	}
+
+	// Answer the callback query first
+	ts.answerCallbackQuery(ctx, callbackQuery.ID, "")
+
+	// Reply in the chat the button was pressed in, which may be a group, by editing
+	// the message the button is on
+	chatID := callbackChatID(callbackQuery)
+	messageID := callbackMessageID(callbackQuery)
 	userID := callbackQuery.From.ID
 
 	switch action {
 	case "subscribe":
-		ts.handleSubscribeCallback(ctx, chatID, userID, param)
+		ts.handleSubscribeCallback(ctx, chatID, userID, messageID, param)
 	case "unsubscribe":
-		ts.handleUnsubscribeCallback(ctx, chatID, userID, param)
+		ts.handleUnsubscribeCallback(ctx, chatID, userID, messageID, param)
+	case "settings":
+		ts.handleSettingsCallback(ctx, chatID, userID, messageID, parts[1:])
 	case "list":
-		ts.handleListCommand(ctx, chatID, userID)
+		ts.handleListCommand(ctx, chatID, userID, messageID)
 	case "types":
-		ts.handleTypesCommand(ctx, chatID, userID)
+		ts.handleTypesCommand(ctx, chatID, userID, messageID)
 	case "help":
-		ts.handleHelpCommand(ctx, chatID, userID)
+		ts.handleHelpCommand(ctx, chatID, userID, messageID)
 	case "admin":
-		if param == "main" {
-			ts.handleAdminCommand(ctx, chatID, userID, "/admin")
-		} else if param == "pending" || param == "approved" || param == "stats" || param == "cleanup" {
-			ts.handleAdminCallback(ctx, chatID, userID, fmt.Sprintf("/admin_%s", param))
-		} else {
-			ts.handleAdminCallback(ctx, chatID, userID, "/admin")
-		}
+		ts.handleAdminCallback(ctx, chatID, userID, messageID, "/admin")
 	default:
 		log.Printf("Unknown callback action: %s", action)
 	}
 }
 
+// adminCallbackActions are the callback actions handled by TelegramAdminService
+var adminCallbackActions = map[string]bool{
+	"admin_menu":   true,
+	"admin_type":   true,
+	"approve_user": true,
+	"reject_user":  true,
+	"disable_user": true,
+	"enable_user":  true,
+	"view_user":    true,
+}
+
+// toAdminCallback converts a callback query to the form TelegramAdminService takes
+func toAdminCallback(callbackQuery *models.CallbackQuery) model.CallbackQuery {
+	message := &model.Message{
+		MessageID: callbackMessageID(callbackQuery),
+		Chat:      model.Chat{ID: callbackChatID(callbackQuery)},
+	}
+
+	return model.CallbackQuery{
+		ID: callbackQuery.ID,
+		From: model.User{
+			ID:        int(callbackQuery.From.ID),
+			FirstName: callbackQuery.From.FirstName,
+			Username:  callbackQuery.From.Username,
+		},
+		Message: message,
+		Data:    callbackQuery.Data,
+	}
+}
+
 // answerCallbackQuery answers a callback query
 func (ts *TelegramBotService) answerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
 	_, err := ts.botInstance.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
@@ -694,33 +1068,33 @@ func (ts *TelegramBotService) answerCallbackQuery(ctx context.Context, callbackQ
 }
 
 // handleSubscribeCallback handles subscription via button callback
-func (ts *TelegramBotService) handleSubscribeCallback(ctx context.Context, chatID, userID int64, notificationType string) {
+func (ts *TelegramBotService) handleSubscribeCallback(ctx context.Context, chatID, userID int64, messageID int, notificationType string) {
 	log.Printf("Subscribe callback: user %d wants to subscribe to %s", userID, notificationType)
 
 	// Use the existing subscribe logic
 	parts := []string{"/subscribe", notificationType}
-	ts.handleSubscribeCommand(ctx, chatID, userID, parts)
+	ts.handleSubscribeCommand(ctx, chatID, userID, messageID, parts)
 }
 
 // handleUnsubscribeCallback handles unsubscription via button callback
-func (ts *TelegramBotService) handleUnsubscribeCallback(ctx context.Context, chatID, userID int64, notificationType string) {
+func (ts *TelegramBotService) handleUnsubscribeCallback(ctx context.Context, chatID, userID int64, messageID int, notificationType string) {
 	log.Printf("Unsubscribe callback: user %d wants to unsubscribe from %s", userID, notificationType)
 
 	// Use the existing unsubscribe logic
 	parts := []string{"/unsubscribe", notificationType}
-	ts.handleUnsubscribeCommand(ctx, chatID, userID, parts)
+	ts.handleUnsubscribeCommand(ctx, chatID, userID, messageID, parts)
 }
 
 // handleAdminCallback handles admin actions via button callback
-func (ts *TelegramBotService) handleAdminCallback(ctx context.Context, chatID, userID int64, command string) {
+func (ts *TelegramBotService) handleAdminCallback(ctx context.Context, chatID, userID int64, messageID int, command string) {
 	log.Printf("Admin callback: user %d executed %s", userID, command)
 
 	// Use the existing admin logic
-	ts.handleAdminCommand(ctx, chatID, userID, command)
+	ts.handleAdminCommand(ctx, chatID, userID, messageID, command)
 }
 
 // showAdminPanel displays the admin panel with buttons
-func (ts *TelegramBotService) showAdminPanel(ctx context.Context, chatID, userID int64) {
+func (ts *TelegramBotService) showAdminPanel(ctx context.Context, chatID, userID int64, messageID int) {
 	message := `🔧 Admin Panel
 
 Welcome to the admin panel! Here you can manage users and system settings.
@@ -759,5 +1133,5 @@ Use the buttons below to perform admin tasks quickly.`
 ⚠️ Note: Some admin features may be limited.`
 	}
 
-	ts.SendMessageWithKeyboard(chatID, message, keyboard)
+	ts.reply(chatID, messageID, message, &keyboard)
 }
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-messaging/entity"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// ResolveChannel implements ChannelResolver using getChat and getChatMember
+func (ts *TelegramBotService) ResolveChannel(ctx context.Context, channel string) (*ChannelInfo, error) {
+	chatID, err := ParseChannelID(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	chat, err := ts.botInstance.GetChat(ctx, &bot.GetChatParams{ChatID: chatID})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrChannelNotFound, channel, err)
+	}
+	if chat.Type != models.ChatTypeChannel {
+		return nil, fmt.Errorf("%w: %s is a %s, not a channel", ErrChannelNotFound, channel, chat.Type)
+	}
+
+	me, err := ts.botInstance.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot info: %w", err)
+	}
+
+	member, err := ts.botInstance.GetChatMember(ctx, &bot.GetChatMemberParams{ChatID: chat.ID, UserID: me.ID})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrChannelCannotPost, channel, err)
+	}
+
+	canPost := member.Type == models.ChatMemberTypeOwner ||
+		(member.Type == models.ChatMemberTypeAdministrator && member.Administrator.CanPostMessages)
+	if !canPost {
+		return nil, fmt.Errorf("%w: make the bot an admin of %s with permission to post messages", ErrChannelCannotPost, channel)
+	}
+
+	return &ChannelInfo{ChatID: chat.ID, Title: chat.Title, Username: chat.Username}, nil
+}
+
+// handleChannelCommand handles /channel add|remove|list <channel> ..., available to admins
+func (ts *TelegramBotService) handleChannelCommand(ctx context.Context, chatID, userID int64, parts []string) {
+	admin, err := ts.userService.GetUserByTelegramID(ctx, userID)
+	if err != nil || admin.Role != "admin" {
+		ts.SendMessage(chatID, "❌ You don't have admin permissions.")
+		return
+	}
+
+	if len(parts) < 3 || (parts[1] != "list" && len(parts) < 4) {
+		ts.SendMessage(chatID, `📢 Channel Subscriptions
+
+Post notifications into a Telegram channel. Add the bot to the channel as an admin allowed to post messages first.
+
+Usage:
+• /channel add <@channel|id> <type> [key=value ...]
+• /channel remove <@channel|id> <type>
+• /channel list <@channel|id>
+
+Example: /channel add @my_alerts price_alert currency=ETH threshold=3500`)
+		return
+	}
+
+	action, channel := strings.ToLower(parts[1]), parts[2]
+
+	switch action {
+	case "add":
+		notificationTypeCode := strings.ToLower(parts[3])
+		notificationType, err := ts.notificationTypeService.GetTypeByCode(ctx, notificationTypeCode)
+		if err != nil {
+			ts.SendMessage(chatID, fmt.Sprintf("❌ Unknown notification type '%s'. Type /types to see available options.", notificationTypeCode))
+			return
+		}
+
+		var preferences *entity.SubscriptionPreferences
+		if len(parts) > 4 {
+			preferences = &entity.SubscriptionPreferences{}
+			if err := ParsePreferenceArgs(notificationType, parts[4:], preferences); err != nil {
+				ts.SendMessage(chatID, fmt.Sprintf("❌ %v", err))
+				return
+			}
+		}
+
+		_, info, err := ts.channelService.SubscribeChannel(ctx, admin.ID, channel, notificationTypeCode, preferences)
+		if err != nil {
+			log.Printf("Failed to subscribe channel %s to %s: %v", channel, notificationTypeCode, err)
+			ts.SendMessage(chatID, fmt.Sprintf("❌ %s", channelErrorMessage(err)))
+			return
+		}
+		ts.SendMessage(chatID, fmt.Sprintf("✅ %s will receive %s notifications.", info.Title, notificationType.Name))
+
+	case "remove":
+		notificationTypeCode := strings.ToLower(parts[3])
+		if err := ts.channelService.UnsubscribeChannel(ctx, admin.ID, channel, notificationTypeCode); err != nil {
+			log.Printf("Failed to unsubscribe channel %s from %s: %v", channel, notificationTypeCode, err)
+			ts.SendMessage(chatID, fmt.Sprintf("❌ %s", channelErrorMessage(err)))
+			return
+		}
+		ts.SendMessage(chatID, fmt.Sprintf("✅ %s no longer receives %s notifications.", channel, notificationTypeCode))
+
+	case "list":
+		subscriptions, info, err := ts.channelService.GetChannelSubscriptions(ctx, channel)
+		if err != nil {
+			ts.SendMessage(chatID, fmt.Sprintf("❌ %s", channelErrorMessage(err)))
+			return
+		}
+		if len(subscriptions) == 0 {
+			ts.SendMessage(chatID, fmt.Sprintf("📢 %s has no subscriptions.", info.Title))
+			return
+		}
+
+		var message strings.Builder
+		message.WriteString(fmt.Sprintf("📢 %s Subscriptions (%d):\n\n", info.Title, len(subscriptions)))
+		for _, sub := range subscriptions {
+			message.WriteString(fmt.Sprintf("🔹 %s (%s)\n", sub.NotificationType.Name, sub.NotificationType.Code))
+		}
+		ts.SendMessage(chatID, message.String())
+
+	default:
+		ts.SendMessage(chatID, "❓ Unknown action. Use /channel add, /channel remove or /channel list.")
+	}
+}
+
+// channelErrorMessage explains channel errors in terms an admin can act on
+func channelErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, ErrChannelNotFound):
+		return "Channel not found. Use the channel's @username or numeric ID, and make sure the bot is a member."
+	case errors.Is(err, ErrChannelCannotPost):
+		return "I can't post in that channel. Make me an admin with permission to post messages."
+	}
+	return err.Error()
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// isPrivateChat reports whether a chat is the user's private chat with the bot,
+// whose ID is always the user's own Telegram ID
+func isPrivateChat(chatID, userID int64) bool {
+	return chatID == userID
+}
+
+// callbackChatID returns the chat a callback button was pressed in. Callbacks from
+// inline-mode messages carry no message, so those fall back to the user's private chat.
+func callbackChatID(callbackQuery *models.CallbackQuery) int64 {
+	switch {
+	case callbackQuery.Message.Message != nil:
+		return callbackQuery.Message.Message.Chat.ID
+	case callbackQuery.Message.InaccessibleMessage != nil:
+		return callbackQuery.Message.InaccessibleMessage.Chat.ID
+	}
+	return callbackQuery.From.ID
+}
+
+// callbackMessageID returns the message a callback button is on, or 0 for inline-mode
+// messages, which are answered with a new message instead of being edited
+func callbackMessageID(callbackQuery *models.CallbackQuery) int {
+	switch {
+	case callbackQuery.Message.Message != nil:
+		return callbackQuery.Message.Message.ID
+	case callbackQuery.Message.InaccessibleMessage != nil:
+		return callbackQuery.Message.InaccessibleMessage.MessageID
+	}
+	return 0
+}
+
+// canManageSubscriptions reports whether a user may change the subscriptions of a chat:
+// anyone in their private chat, only the owner and administrators in a group
+func (ts *TelegramBotService) canManageSubscriptions(ctx context.Context, chatID, userID int64) bool {
+	if isPrivateChat(chatID, userID) {
+		return true
+	}
+
+	isAdmin, err := ts.IsChatAdmin(ctx, chatID, userID)
+	if err != nil {
+		log.Printf("Failed to get chat member %d in chat %d: %v", userID, chatID, err)
+		return false
+	}
+	return isAdmin
+}
+
+// IsChatAdmin reports whether a user is the owner or an administrator of a group or channel
+func (ts *TelegramBotService) IsChatAdmin(ctx context.Context, chatID, userID int64) (bool, error) {
+	member, err := ts.botInstance.GetChatMember(ctx, &bot.GetChatMemberParams{
+		ChatID: chatID,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return member.Type == models.ChatMemberTypeOwner || member.Type == models.ChatMemberTypeAdministrator, nil
+}
+
+// requireChatAdmin replies with an explanation and returns false when the user may not
+// change this chat's subscriptions
+func (ts *TelegramBotService) requireChatAdmin(ctx context.Context, chatID, userID int64) bool {
+	if ts.canManageSubscriptions(ctx, chatID, userID) {
+		return true
+	}
+
+	ts.SendMessage(chatID, "🔒 Only group admins can change this group's subscriptions.")
+	return false
+}
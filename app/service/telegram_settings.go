@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+)
+
+// settingsFlow identifies the /settings dialogue in the conversation store
+const settingsFlow = "settings"
+
+// handleSettingsCommand handles /settings [type]
+func (ts *TelegramBotService) handleSettingsCommand(ctx context.Context, chatID, userID int64, parts []string) {
+	if !ts.requireChatAdmin(ctx, chatID, userID) {
+		return
+	}
+
+	if len(parts) < 2 {
+		ts.showSettingsMenu(ctx, chatID, userID)
+		return
+	}
+
+	ts.showSettings(ctx, chatID, userID, 0, strings.ToLower(parts[1]), "")
+}
+
+// showSettingsMenu lets the user pick which subscription to edit
+func (ts *TelegramBotService) showSettingsMenu(ctx context.Context, chatID, userID int64) {
+	subscriptions, err := ts.subscriptionService.GetChatSubscriptions(ctx, chatID)
+	if err != nil {
+		log.Printf("Failed to get subscriptions for chat %d: %v", chatID, err)
+		ts.SendMessage(chatID, "❌ Failed to retrieve your subscriptions.")
+		return
+	}
+
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{},
+	}
+	for _, sub := range subscriptions {
+		if sub.IsActive {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+				{Text: fmt.Sprintf("⚙️ %s", sub.NotificationType.Name), CallbackData: fmt.Sprintf("settings:%s", sub.NotificationType.Code)},
+			})
+		}
+	}
+
+	if len(keyboard.InlineKeyboard) == 0 {
+		ts.SendMessage(chatID, "📝 You have no subscriptions to configure yet. Type /types to get started.")
+		return
+	}
+
+	ts.SendMessageWithKeyboard(chatID, "⚙️ Settings\n\nWhich subscription would you like to change?", keyboard)
+}
+
+// showSettings shows the current preferences of a subscription with a button per field,
+// headed by notice when it is not empty
+func (ts *TelegramBotService) showSettings(ctx context.Context, chatID, userID int64, messageID int, notificationTypeCode, notice string) {
+	subscription, err := ts.findSubscription(ctx, chatID, notificationTypeCode)
+	if err != nil {
+		ts.replyText(chatID, messageID, fmt.Sprintf("❌ You are not subscribed to '%s'. Type /list to see your subscriptions.", notificationTypeCode))
+		return
+	}
+
+	var message strings.Builder
+	if notice != "" {
+		message.WriteString(notice + "\n\n")
+	}
+	message.WriteString(fmt.Sprintf("⚙️ %s Settings\n\n", subscription.NotificationType.Name))
+
+	keyboard := model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{},
+	}
+
+	for _, field := range PreferenceFields(&subscription.NotificationType) {
+		value := field.Get(&subscription.Preferences)
+		if value == "" {
+			value = "default"
+		}
+		message.WriteString(fmt.Sprintf("%s: %s\n", field.Label, value))
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+			{Text: fmt.Sprintf("Change %s", field.Label), CallbackData: fmt.Sprintf("settings:%s:%s", notificationTypeCode, field.Key)},
+		})
+	}
+
+	message.WriteString("\nPick a setting to change it.")
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []model.InlineKeyboardButton{
+		{Text: "✅ Done", CallbackData: fmt.Sprintf("settings:%s:done", notificationTypeCode)},
+	})
+
+	ts.reply(chatID, messageID, message.String(), &keyboard)
+}
+
+// handleSettingsCallback handles settings:<type>[:<field>[:<value>]] buttons, editing
+// the message the button is on as the dialogue moves along
+func (ts *TelegramBotService) handleSettingsCallback(ctx context.Context, chatID, userID int64, messageID int, args []string) {
+	if !ts.requireChatAdmin(ctx, chatID, userID) {
+		return
+	}
+
+	notificationTypeCode := args[0]
+
+	if len(args) == 1 {
+		ts.showSettings(ctx, chatID, userID, messageID, notificationTypeCode, "")
+		return
+	}
+
+	key := args[1]
+	if key == "done" {
+		ts.conversations.End(chatID, userID)
+		ts.reply(chatID, messageID, "✅ Settings saved.", &model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{
+				{{Text: "📱 My Subscriptions", CallbackData: "list:mine"}},
+			},
+		})
+		return
+	}
+
+	notificationType, err := ts.notificationTypeService.GetTypeByCode(ctx, notificationTypeCode)
+	if err != nil {
+		log.Printf("Failed to get notification type %s: %v", notificationTypeCode, err)
+		return
+	}
+
+	field, ok := FindPreferenceField(notificationType, key)
+	if !ok {
+		log.Printf("Unknown settings field %s for %s", key, notificationTypeCode)
+		return
+	}
+
+	// A choice button carries the value itself
+	if len(args) > 2 {
+		ts.saveSetting(ctx, chatID, userID, messageID, notificationTypeCode, field, args[2])
+		return
+	}
+
+	backToSettings := []model.InlineKeyboardButton{
+		{Text: "🔙 Back", CallbackData: fmt.Sprintf("settings:%s", notificationTypeCode)},
+	}
+
+	if len(field.Options) > 0 {
+		row := []model.InlineKeyboardButton{}
+		for _, option := range field.Options {
+			row = append(row, model.InlineKeyboardButton{
+				Text:         option,
+				CallbackData: fmt.Sprintf("settings:%s:%s:%s", notificationTypeCode, field.Key, option),
+			})
+		}
+		ts.reply(chatID, messageID, field.Prompt, &model.InlineKeyboardMarkup{
+			InlineKeyboard: [][]model.InlineKeyboardButton{row, backToSettings},
+		})
+		return
+	}
+
+	// Free-text fields wait for the user's next message
+	ts.conversations.Start(chatID, userID, model.Conversation{
+		Flow:             settingsFlow,
+		NotificationType: notificationTypeCode,
+		Field:            field.Key,
+	})
+	ts.reply(chatID, messageID, fmt.Sprintf("%s\n\nType /cancel to stop.", field.Prompt), &model.InlineKeyboardMarkup{
+		InlineKeyboard: [][]model.InlineKeyboardButton{backToSettings},
+	})
+}
+
+// handleConversationReply consumes a free-text reply to a pending prompt.
+// It returns false if there is no active conversation for this user and chat.
+func (ts *TelegramBotService) handleConversationReply(ctx context.Context, chatID, userID int64, text string) bool {
+	conversation := ts.conversations.Get(chatID, userID)
+	if conversation == nil || conversation.Flow != settingsFlow {
+		return false
+	}
+
+	notificationType, err := ts.notificationTypeService.GetTypeByCode(ctx, conversation.NotificationType)
+	if err != nil {
+		ts.conversations.End(chatID, userID)
+		return false
+	}
+
+	field, ok := FindPreferenceField(notificationType, conversation.Field)
+	if !ok {
+		ts.conversations.End(chatID, userID)
+		return false
+	}
+
+	ts.saveSetting(ctx, chatID, userID, 0, conversation.NotificationType, field, text)
+	return true
+}
+
+// handleCancelCommand abandons the current dialogue
+func (ts *TelegramBotService) handleCancelCommand(ctx context.Context, chatID, userID int64) {
+	if ts.conversations.Get(chatID, userID) == nil {
+		ts.SendMessage(chatID, "Nothing to cancel.")
+		return
+	}
+
+	ts.conversations.End(chatID, userID)
+	ts.SendMessage(chatID, "👌 Cancelled.")
+}
+
+// saveSetting validates and stores one preference, then shows the updated settings.
+// A choice button (messageID set) updates its own message; a typed reply gets a new one.
+func (ts *TelegramBotService) saveSetting(ctx context.Context, chatID, userID int64, messageID int, notificationTypeCode string, field PreferenceField, value string) {
+	subscription, err := ts.findSubscription(ctx, chatID, notificationTypeCode)
+	if err != nil {
+		ts.conversations.End(chatID, userID)
+		ts.replyText(chatID, messageID, fmt.Sprintf("❌ You are not subscribed to '%s' anymore.", notificationTypeCode))
+		return
+	}
+
+	preferences := subscription.Preferences
+	if err := field.Set(&preferences, value); err != nil {
+		// Keep the conversation open so the user can try again
+		ts.SendMessage(chatID, fmt.Sprintf("❌ %v\n\n%s", err, field.Prompt))
+		return
+	}
+
+	if err := ts.subscriptionService.UpdatePreferences(ctx, userID, chatID, notificationTypeCode, &preferences); err != nil {
+		log.Printf("Failed to update %s preferences for user %d: %v", notificationTypeCode, userID, err)
+		ts.SendMessage(chatID, fmt.Sprintf("❌ Failed to save: %v", err))
+		return
+	}
+
+	ts.conversations.End(chatID, userID)
+	ts.showSettings(ctx, chatID, userID, messageID, notificationTypeCode, fmt.Sprintf("✅ %s updated.", field.Label))
+}
+
+// findSubscription returns the chat's active subscription to a notification type
+func (ts *TelegramBotService) findSubscription(ctx context.Context, chatID int64, notificationTypeCode string) (*entity.Subscription, error) {
+	subscriptions, err := ts.subscriptionService.GetChatSubscriptions(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subscriptions {
+		if sub.IsActive && sub.NotificationType.Code == notificationTypeCode {
+			return sub, nil
+		}
+	}
+	return nil, fmt.Errorf("subscription not found")
+}
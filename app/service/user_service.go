@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go-messaging/entity"
+	"go-messaging/internal/schedule"
 	"go-messaging/repository"
 
 	"github.com/google/uuid"
@@ -15,6 +16,7 @@ import (
 // UserServiceImpl implements UserService
 type UserServiceImpl struct {
 	userRepo repository.UserRepository
+	notifier RegistrationNotifier
 }
 
 // NewUserService creates a new user service
@@ -54,6 +56,7 @@ func (s *UserServiceImpl) CreateOrUpdateUser(ctx context.Context, telegramUserID
 		LastName:       lastName,
 		LanguageCode:   languageCode,
 		IsBot:          isBot,
+		ApprovalStatus: "pending",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -62,9 +65,19 @@ func (s *UserServiceImpl) CreateOrUpdateUser(ctx context.Context, telegramUserID
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// Tell admins in the background so the new user's request is not held up
+	if s.notifier != nil {
+		registered := *user
+		go s.notifier.NotifyPendingUser(context.WithoutCancel(ctx), &registered)
+	}
+
 	return user, nil
 }
 
+func (s *UserServiceImpl) SetRegistrationNotifier(notifier RegistrationNotifier) {
+	s.notifier = notifier
+}
+
 func (s *UserServiceImpl) GetUserByTelegramID(ctx context.Context, telegramUserID int64) (*entity.User, error) {
 	user, err := s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
 	if err != nil {
@@ -95,6 +108,24 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *entity.User) err
 	return nil
 }
 
+func (s *UserServiceImpl) UpdateTimezone(ctx context.Context, telegramUserID int64, timezone string) error {
+	loc, err := schedule.LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.Timezone = loc.String()
+	return s.UpdateUser(ctx, user)
+}
+
 func (s *UserServiceImpl) DeleteUser(ctx context.Context, telegramUserID int64) error {
 	user, err := s.userRepo.GetByTelegramUserID(ctx, telegramUserID)
 	if err != nil {
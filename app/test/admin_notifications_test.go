@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+	"go-messaging/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentMessage struct {
+	chatID    int64
+	messageID int
+	text      string
+}
+
+// recordingAdminSender records messages and edits made by the admin flows
+type recordingAdminSender struct {
+	fakeSender
+	mu     sync.Mutex
+	nextID int
+	sent   []sentMessage
+	edited []sentMessage
+}
+
+func (r *recordingAdminSender) SendMessageWithKeyboard(chatID int64, message string, keyboard model.InlineKeyboardMarkup) error {
+	_, err := r.SendMessageWithKeyboardID(chatID, message, keyboard)
+	return err
+}
+
+func (r *recordingAdminSender) SendMessageWithKeyboardID(chatID int64, message string, keyboard model.InlineKeyboardMarkup) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.sent = append(r.sent, sentMessage{chatID: chatID, messageID: r.nextID, text: message})
+	return r.nextID, nil
+}
+
+func (r *recordingAdminSender) EditMessageWithKeyboard(chatID int64, messageID int, message string, keyboard *model.InlineKeyboardMarkup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.edited = append(r.edited, sentMessage{chatID: chatID, messageID: messageID, text: message})
+	return nil
+}
+
+func (r *recordingAdminSender) AnswerCallbackQuery(callbackID, text string) error {
+	return nil
+}
+
+// memoryAdminService keeps users and approval notifications in memory
+type memoryAdminService struct {
+	service.AdminServiceInterface
+	users         map[uuid.UUID]*entity.User
+	notifications map[uuid.UUID]map[int64]int // user -> admin chat -> message ID
+}
+
+func (m *memoryAdminService) GetAdmins(ctx context.Context) ([]entity.User, error) {
+	var admins []entity.User
+	for _, user := range m.users {
+		if user.Role == "admin" && user.ApprovalStatus == "approved" {
+			admins = append(admins, *user)
+		}
+	}
+	return admins, nil
+}
+
+func (m *memoryAdminService) IsAdmin(ctx context.Context, telegramUserID int64) (bool, error) {
+	for _, user := range m.users {
+		if user.TelegramUserID == telegramUserID {
+			return user.Role == "admin", nil
+		}
+	}
+	return false, errors.New("user not found")
+}
+
+func (m *memoryAdminService) GetPendingUsers(ctx context.Context) ([]entity.User, error) {
+	var pending []entity.User
+	for _, user := range m.users {
+		if user.ApprovalStatus == "pending" {
+			pending = append(pending, *user)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryAdminService) GetUsersPage(ctx context.Context, status string, offset, limit int) ([]entity.User, int64, error) {
+	var matching []entity.User
+	for _, user := range m.users {
+		if user.ApprovalStatus == status {
+			matching = append(matching, *user)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].TelegramUserID < matching[j].TelegramUserID })
+
+	if offset >= len(matching) {
+		return nil, int64(len(matching)), nil
+	}
+	return matching[offset:min(offset+limit, len(matching))], int64(len(matching)), nil
+}
+
+func (m *memoryAdminService) FindUsers(ctx context.Context, query string, limit int) ([]entity.User, error) {
+	var found []entity.User
+	for _, user := range m.users {
+		if user.Username != nil && strings.Contains(*user.Username, query) {
+			found = append(found, *user)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryAdminService) ApproveUser(ctx context.Context, userID uuid.UUID, adminID uuid.UUID) error {
+	if m.users[userID].ApprovalStatus == "approved" {
+		return fmt.Errorf("user is already approved")
+	}
+	m.users[userID].ApprovalStatus = "approved"
+	return nil
+}
+
+func (m *memoryAdminService) ClaimApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64) (bool, error) {
+	if m.notifications[userID] == nil {
+		m.notifications[userID] = make(map[int64]int)
+	}
+	if _, ok := m.notifications[userID][adminChatID]; ok {
+		return false, nil
+	}
+	m.notifications[userID][adminChatID] = 0
+	return true, nil
+}
+
+func (m *memoryAdminService) RecordApprovalNotification(ctx context.Context, userID uuid.UUID, adminChatID int64, messageID int) error {
+	m.notifications[userID][adminChatID] = messageID
+	return nil
+}
+
+func (m *memoryAdminService) TakeApprovalNotifications(ctx context.Context, userID uuid.UUID) ([]*entity.ApprovalNotification, error) {
+	var taken []*entity.ApprovalNotification
+	for chatID, messageID := range m.notifications[userID] {
+		taken = append(taken, &entity.ApprovalNotification{UserID: userID, AdminChatID: chatID, MessageID: messageID})
+	}
+	delete(m.notifications, userID)
+	return taken, nil
+}
+
+// memoryUserDirectory looks users up in the admin service's map
+type memoryUserDirectory struct {
+	service.UserService
+	admins *memoryAdminService
+}
+
+func (d *memoryUserDirectory) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	if user, ok := d.admins.users[id]; ok {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (d *memoryUserDirectory) GetUserByTelegramID(ctx context.Context, telegramUserID int64) (*entity.User, error) {
+	for _, user := range d.admins.users {
+		if user.TelegramUserID == telegramUserID {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func newApprovalFixture() (*service.TelegramAdminService, *recordingAdminSender, *memoryAdminService, *entity.User) {
+	name := "newcomer"
+	pending := &entity.User{ID: uuid.New(), TelegramUserID: 300, Username: &name, ApprovalStatus: "pending"}
+	admins := &memoryAdminService{
+		users: map[uuid.UUID]*entity.User{
+			pending.ID: pending,
+		},
+		notifications: make(map[uuid.UUID]map[int64]int),
+	}
+	for _, telegramUserID := range []int64{100, 200} {
+		admin := &entity.User{ID: uuid.New(), TelegramUserID: telegramUserID, Role: "admin", ApprovalStatus: "approved"}
+		admins.users[admin.ID] = admin
+	}
+
+	sender := &recordingAdminSender{}
+	telegramAdmin := service.NewTelegramAdminService(sender, admins, &memoryUserDirectory{admins: admins}, nil)
+	return telegramAdmin, sender, admins, pending
+}
+
+func TestNotifyPendingUser_TellsEachAdminOnce(t *testing.T) {
+	telegramAdmin, sender, _, pending := newApprovalFixture()
+
+	telegramAdmin.NotifyPendingUser(context.Background(), pending)
+	telegramAdmin.NotifyPendingUser(context.Background(), pending)
+
+	require.Len(t, sender.sent, 2)
+	chats := []int64{sender.sent[0].chatID, sender.sent[1].chatID}
+	assert.ElementsMatch(t, []int64{100, 200}, chats)
+	assert.Contains(t, sender.sent[0].text, "@newcomer")
+}
+
+func TestApproveFromNotification_EditsEveryAdminsMessage(t *testing.T) {
+	telegramAdmin, sender, admins, pending := newApprovalFixture()
+	telegramAdmin.NotifyPendingUser(context.Background(), pending)
+	require.Len(t, sender.sent, 2)
+
+	// The admin in chat 100 presses Approve on their notification
+	var pressed sentMessage
+	for _, message := range sender.sent {
+		if message.chatID == 100 {
+			pressed = message
+		}
+	}
+	telegramAdmin.HandleCallbackQuery(context.Background(), model.CallbackQuery{
+		ID:      "callback",
+		From:    model.User{ID: 100},
+		Message: &model.Message{MessageID: pressed.messageID, Chat: model.Chat{ID: 100}},
+		Data:    "approve_user:" + pending.ID.String(),
+	})
+
+	assert.Equal(t, "approved", admins.users[pending.ID].ApprovalStatus)
+	require.Len(t, sender.edited, 2)
+	for _, edit := range sender.edited {
+		assert.True(t, strings.Contains(edit.text, "✅ Approved by"), edit.text)
+	}
+
+	// Pressing on the notification does not post a fresh pending list
+	assert.Len(t, sender.sent, 2)
+}
+
+func TestAdminMenuCallback_EditsInPlaceWithPaging(t *testing.T) {
+	telegramAdmin, sender, admins, _ := newApprovalFixture()
+	for i := 0; i < 6; i++ {
+		user := &entity.User{ID: uuid.New(), TelegramUserID: int64(400 + i), ApprovalStatus: "pending"}
+		admins.users[user.ID] = user
+	}
+
+	press := func(data string) {
+		telegramAdmin.HandleCallbackQuery(context.Background(), model.CallbackQuery{
+			ID:      "callback",
+			From:    model.User{ID: 100},
+			Message: &model.Message{MessageID: 77, Chat: model.Chat{ID: 100}},
+			Data:    data,
+		})
+	}
+
+	// Seven pending users make two pages of five
+	press("admin_menu:pending")
+	require.Len(t, sender.edited, 1)
+	assert.Equal(t, 77, sender.edited[0].messageID)
+	assert.Contains(t, sender.edited[0].text, "page 1/2")
+
+	press("admin_menu:pending:1")
+	require.Len(t, sender.edited, 2)
+	assert.Contains(t, sender.edited[1].text, "page 2/2")
+
+	// Nothing was sent as a new message
+	assert.Empty(t, sender.sent)
+}
+
+func TestAdminMenuCallback_PastLastPageShowsLastPage(t *testing.T) {
+	telegramAdmin, sender, _, _ := newApprovalFixture()
+
+	// The one pending user fits on the first page
+	telegramAdmin.HandleCallbackQuery(context.Background(), model.CallbackQuery{
+		ID:      "callback",
+		From:    model.User{ID: 100},
+		Message: &model.Message{MessageID: 77, Chat: model.Chat{ID: 100}},
+		Data:    "admin_menu:pending:3",
+	})
+
+	require.Len(t, sender.edited, 1)
+	assert.Contains(t, sender.edited[0].text, "page 1/1")
+	assert.Contains(t, sender.edited[0].text, "@newcomer")
+}
+
+func TestAdminFind(t *testing.T) {
+	telegramAdmin, sender, admins, pending := newApprovalFixture()
+	other := "newcomer2"
+	second := &entity.User{ID: uuid.New(), TelegramUserID: 301, Username: &other, ApprovalStatus: "approved"}
+	admins.users[second.ID] = second
+
+	find := func(query string) string {
+		telegramAdmin.HandleAdminCommand(context.Background(), model.Message{
+			From: model.User{ID: 100},
+			Chat: model.Chat{ID: 100},
+			Text: "/admin_find " + query,
+		})
+		require.NotEmpty(t, sender.sent)
+		return sender.sent[len(sender.sent)-1].text
+	}
+
+	// A single match opens the user
+	assert.Contains(t, find("newcomer2"), "User Details")
+
+	// Several matches are listed
+	listed := find("newcomer")
+	assert.Contains(t, listed, "Users matching")
+	assert.Contains(t, listed, pending.ApprovalStatus)
+
+	assert.Contains(t, find("nobody"), "No users match")
+}
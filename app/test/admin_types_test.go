@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDelivery "go-messaging/delivery/http"
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/model"
+	"go-messaging/repository"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryTypeRepository keeps notification types in memory
+type memoryTypeRepository struct {
+	repository.NotificationTypeRepository
+	types []*entity.NotificationType
+}
+
+func (r *memoryTypeRepository) GetAll(ctx context.Context) ([]*entity.NotificationType, error) {
+	return r.types, nil
+}
+
+func (r *memoryTypeRepository) GetByID(ctx context.Context, id int) (*entity.NotificationType, error) {
+	for _, notificationType := range r.types {
+		if notificationType.ID == id {
+			copied := *notificationType
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTypeRepository) GetByCode(ctx context.Context, code string) (*entity.NotificationType, error) {
+	for _, notificationType := range r.types {
+		if notificationType.Code == code {
+			copied := *notificationType
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTypeRepository) Create(ctx context.Context, notificationType *entity.NotificationType) error {
+	notificationType.ID = len(r.types) + 1
+	r.types = append(r.types, notificationType)
+	return nil
+}
+
+func (r *memoryTypeRepository) Update(ctx context.Context, notificationType *entity.NotificationType) error {
+	for i, existing := range r.types {
+		if existing.ID == notificationType.ID {
+			r.types[i] = notificationType
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// countingReloader counts schedule reloads
+type countingReloader struct {
+	reloads int
+}
+
+func (c *countingReloader) TriggerReload() {
+	c.reloads++
+}
+
+func newTypeFixture() (*service.TelegramAdminService, *recordingAdminSender, *memoryTypeRepository, *countingReloader) {
+	types := &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", Name: "Coinbase Alerts", DefaultIntervalMinutes: 1, IsActive: true},
+	}}
+	reloader := &countingReloader{}
+	typeService := service.NewNotificationTypeService(types, nil)
+	typeService.SetChangeListener(reloader)
+
+	_, _, admins, _ := newApprovalFixture()
+	sender := &recordingAdminSender{}
+	telegramAdmin := service.NewTelegramAdminService(sender, admins, &memoryUserDirectory{admins: admins}, typeService)
+	return telegramAdmin, sender, types, reloader
+}
+
+func TestNotificationTypeService_ValidatesAndNotifiesChanges(t *testing.T) {
+	types := &memoryTypeRepository{}
+	reloader := &countingReloader{}
+	typeService := service.NewNotificationTypeService(types, nil)
+	typeService.SetChangeListener(reloader)
+
+	_, err := typeService.CreateType(context.Background(), "Gold:Price", "Gold", nil, 30, entity.PreferenceSchema{})
+	assert.True(t, errors.Is(err, service.ErrInvalidTypeCode), "%v", err)
+	_, err = typeService.CreateType(context.Background(), "gold", "Gold", nil, 0, entity.PreferenceSchema{})
+	assert.True(t, errors.Is(err, service.ErrInvalidTypeInterval), "%v", err)
+	assert.Equal(t, 0, reloader.reloads)
+
+	_, err = typeService.CreateType(context.Background(), "gold", "Gold", nil, 30, entity.PreferenceSchema{})
+	require.NoError(t, err)
+	require.NoError(t, typeService.DeactivateType(context.Background(), "gold"))
+	require.NoError(t, typeService.ActivateType(context.Background(), "gold"))
+	assert.Equal(t, 3, reloader.reloads)
+}
+
+func TestAdminTypeToggle_AsksBeforeDeactivating(t *testing.T) {
+	telegramAdmin, sender, types, reloader := newTypeFixture()
+
+	telegramAdmin.HandleAdminCommand(context.Background(), model.Message{
+		From: model.User{ID: 100},
+		Chat: model.Chat{ID: 100},
+		Text: "/admin_type_toggle coinbase",
+	})
+
+	// Nothing changes until the admin confirms
+	require.Len(t, sender.sent, 1)
+	assert.Contains(t, sender.sent[0].text, "Deactivate Coinbase Alerts?")
+	assert.True(t, types.types[0].IsActive)
+
+	telegramAdmin.HandleCallbackQuery(context.Background(), model.CallbackQuery{
+		ID:      "callback",
+		From:    model.User{ID: 100},
+		Message: &model.Message{MessageID: sender.sent[0].messageID, Chat: model.Chat{ID: 100}},
+		Data:    "admin_type:active:1:0",
+	})
+
+	assert.False(t, types.types[0].IsActive)
+	assert.Equal(t, 1, reloader.reloads)
+	require.Len(t, sender.edited, 1)
+	assert.Contains(t, sender.edited[0].text, "Deactivated `coinbase`")
+}
+
+func TestAdminTypeCreate_RequiresConfirmationFromSameAdmin(t *testing.T) {
+	telegramAdmin, sender, types, reloader := newTypeFixture()
+
+	telegramAdmin.HandleAdminCommand(context.Background(), model.Message{
+		From: model.User{ID: 100},
+		Chat: model.Chat{ID: 100},
+		Text: "/admin_type_create gold 30 Gold Prices",
+	})
+	require.Len(t, sender.sent, 1)
+	assert.Contains(t, sender.sent[0].text, "Name: Gold Prices")
+	assert.Len(t, types.types, 1)
+
+	confirm := func(adminID int) {
+		telegramAdmin.HandleCallbackQuery(context.Background(), model.CallbackQuery{
+			ID:      "callback",
+			From:    model.User{ID: adminID},
+			Message: &model.Message{MessageID: sender.sent[0].messageID, Chat: model.Chat{ID: int64(adminID)}},
+			Data:    "admin_type:create",
+		})
+	}
+
+	// Another admin has nothing pending to confirm
+	confirm(200)
+	assert.Len(t, types.types, 1)
+
+	confirm(100)
+	require.Len(t, types.types, 2)
+	assert.Equal(t, "gold", types.types[1].Code)
+	assert.Equal(t, "Gold Prices", types.types[1].Name)
+	assert.Equal(t, 30, types.types[1].DefaultIntervalMinutes)
+	assert.Equal(t, 1, reloader.reloads)
+}
+
+func (r *memoryTypeRepository) DeleteWithSubscriptions(ctx context.Context, id int) error {
+	for i, notificationType := range r.types {
+		if notificationType.ID == id {
+			r.types = append(r.types[:i], r.types[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// countingSubscriptionRepository reports fixed active subscription counts
+type countingSubscriptionRepository struct {
+	repository.SubscriptionRepository
+	counts map[int]int64
+}
+
+func (r *countingSubscriptionRepository) CountActiveByType(ctx context.Context) (map[int]int64, error) {
+	return r.counts, nil
+}
+
+func TestNotificationTypeAdminAPI_DeleteNeedsForceWithSubscribers(t *testing.T) {
+	types := &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", Name: "Coinbase Alerts", DefaultIntervalMinutes: 1, IsActive: true},
+		{ID: 2, Code: "weather", Name: "Weather Updates", DefaultIntervalMinutes: 4, IsActive: true},
+	}}
+	subscriptions := &countingSubscriptionRepository{counts: map[int]int64{1: 3}}
+	typeService := service.NewNotificationTypeService(types, subscriptions)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes := &httpDelivery.RouteConfig{
+		Router:                  router,
+		AdminHandler:            httpDelivery.NewAdminHandler(nil),
+		NotificationTypeHandler: httpDelivery.NewNotificationTypeHandler(typeService),
+	}
+	routes.Setup()
+
+	request := func(method, path string, authenticated bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if authenticated {
+			req.SetBasicAuth("admin", "admin123")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodDelete, "/api/v1/admin/notification-types/weather", false).Code)
+
+	// Subscriber counts are listed per type
+	w := request(http.MethodGet, "/api/v1/admin/notification-types", true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed struct {
+		NotificationTypes []dto.NotificationTypeStatsResponse `json:"notification_types"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.NotificationTypes, 2)
+	assert.Equal(t, int64(3), listed.NotificationTypes[0].ActiveSubscriptions)
+
+	assert.Equal(t, http.StatusConflict, request(http.MethodDelete, "/api/v1/admin/notification-types/coinbase", true).Code)
+	assert.Len(t, types.types, 2)
+
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/api/v1/admin/notification-types/weather", true).Code)
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/api/v1/admin/notification-types/coinbase?force=true", true).Code)
+	assert.Empty(t, types.types)
+}
+
+func TestNotificationTypeAdminAPI_ValidatesPreferenceSchema(t *testing.T) {
+	types := &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", Name: "Coinbase Alerts", DefaultIntervalMinutes: 1, IsActive: true},
+	}}
+	typeService := service.NewNotificationTypeService(types, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes := &httpDelivery.RouteConfig{
+		Router:                  router,
+		AdminHandler:            httpDelivery.NewAdminHandler(nil),
+		NotificationTypeHandler: httpDelivery.NewNotificationTypeHandler(typeService),
+	}
+	routes.Setup()
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth("admin", "admin123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	invalid := `{"properties": {"limit": {"type": "integer", "minimum": 10, "maximum": 5}}, "required": ["city"]}`
+	w := request(http.MethodPost, "/api/v1/admin/notification-types",
+		`{"code": "gold", "name": "Gold", "default_interval_minutes": 30, "preference_schema": `+invalid+`}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Len(t, types.types, 1)
+
+	w = request(http.MethodPut, "/api/v1/admin/notification-types/coinbase", `{"preference_schema": `+invalid+`}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Empty(t, types.types[0].PreferenceSchema.Properties)
+
+	// A valid schema is stored with the new type
+	w = request(http.MethodPost, "/api/v1/admin/notification-types",
+		`{"code": "gold", "name": "Gold", "default_interval_minutes": 30, "preference_schema": {"properties": {"limit": {"type": "integer", "minimum": 1, "default": 3}}}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.Len(t, types.types, 2)
+	assert.Contains(t, types.types[1].PreferenceSchema.Properties, "limit")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/repository"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryUserRepository keeps users in a map keyed by Telegram user ID
+type memoryUserRepository struct {
+	repository.UserRepository
+	users map[int64]*entity.User
+}
+
+func (r *memoryUserRepository) GetByTelegramUserID(ctx context.Context, telegramUserID int64) (*entity.User, error) {
+	if user, ok := r.users[telegramUserID]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *entity.User) error {
+	r.users[user.TelegramUserID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.users[user.TelegramUserID] = user
+	return nil
+}
+
+// channelNotifier forwards pending users to a channel
+type channelNotifier chan *entity.User
+
+func (n channelNotifier) NotifyPendingUser(ctx context.Context, user *entity.User) {
+	n <- user
+}
+
+func TestSubscribe_RequiresApprovedUser(t *testing.T) {
+	users := &memoryUserRepository{users: map[int64]*entity.User{
+		1: {TelegramUserID: 1, ApprovalStatus: "pending"},
+		2: {TelegramUserID: 2, ApprovalStatus: "disabled"},
+	}}
+	subscriptions := service.NewSubscriptionService(nil, users, nil, nil, nil)
+
+	for telegramUserID, status := range map[int64]string{1: "pending", 2: "disabled"} {
+		_, err := subscriptions.Subscribe(context.Background(), telegramUserID, telegramUserID, "coinbase", nil)
+
+		var notApproved *service.UserNotApprovedError
+		require.True(t, errors.As(err, &notApproved), "user %d: %v", telegramUserID, err)
+		assert.Equal(t, status, notApproved.Status)
+	}
+}
+
+func TestCreateOrUpdateUser_NotifiesAdminsOfNewUsers(t *testing.T) {
+	users := &memoryUserRepository{users: make(map[int64]*entity.User)}
+	notified := make(channelNotifier, 2)
+	userService := service.NewUserService(users)
+	userService.SetRegistrationNotifier(notified)
+
+	name := "alice"
+	user, err := userService.CreateOrUpdateUser(context.Background(), 42, &name, &name, nil, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", user.ApprovalStatus)
+
+	select {
+	case pending := <-notified:
+		assert.Equal(t, int64(42), pending.TelegramUserID)
+	case <-time.After(time.Second):
+		t.Fatal("admins were not notified about the new user")
+	}
+
+	// Returning users are not announced again
+	_, err = userService.CreateOrUpdateUser(context.Background(), 42, &name, &name, nil, nil, false)
+	require.NoError(t, err)
+
+	select {
+	case <-notified:
+		t.Fatal("admins were notified about an existing user")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	httpDelivery "go-messaging/delivery/http"
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activeSubscriptionService serves a fixed list of active subscriptions
+type activeSubscriptionService struct {
+	service.SubscriptionService
+	active []*entity.Subscription
+}
+
+func (f *activeSubscriptionService) GetActiveSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error) {
+	return f.active, nil
+}
+
+// recordingDispatcher records broadcast messages, failing for the chats in fail
+type recordingDispatcher struct {
+	service.NotificationDispatchService
+
+	mutex sync.Mutex
+	sent  map[int64]string
+	fail  map[int64]bool
+}
+
+func (r *recordingDispatcher) DispatchToSubscription(ctx context.Context, subscription *entity.Subscription, message string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.fail[subscription.ChatID] {
+		return errors.New("chat not found")
+	}
+	r.sent[subscription.ChatID] = message
+	return nil
+}
+
+func (r *recordingDispatcher) sentCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.sent)
+}
+
+func TestBroadcastAPI_DryRunThenPollProgress(t *testing.T) {
+	types := &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "maintenance", Name: "Maintenance", DefaultIntervalMinutes: 60, IsActive: true},
+	}}
+	subscriptions := &activeSubscriptionService{active: []*entity.Subscription{
+		{ID: 1, ChatID: 10}, {ID: 2, ChatID: 20}, {ID: 3, ChatID: 30},
+	}}
+	dispatcher := &recordingDispatcher{sent: make(map[int64]string), fail: map[int64]bool{30: true}}
+	broadcasts := service.NewBroadcastService(service.NewNotificationTypeService(types, nil), subscriptions, dispatcher)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes := &httpDelivery.RouteConfig{
+		Router:           router,
+		AdminHandler:     httpDelivery.NewAdminHandler(nil),
+		BroadcastHandler: httpDelivery.NewBroadcastHandler(broadcasts),
+	}
+	routes.Setup()
+
+	broadcast := func(code string, req dto.BroadcastRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/admin/notification-types/"+code+"/broadcast", bytes.NewBuffer(body))
+		r.SetBasicAuth("admin", "admin123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	poll := func(id string) service.BroadcastJob {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/broadcasts/"+id, nil)
+		r.SetBasicAuth("admin", "admin123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var job service.BroadcastJob
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job
+	}
+
+	assert.Equal(t, http.StatusNotFound, broadcast("unknown", dto.BroadcastRequest{Message: "hello"}).Code)
+
+	// A dry run counts recipients without sending anything
+	w := broadcast("maintenance", dto.BroadcastRequest{Message: "Maintenance tonight", DryRun: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var dryRun service.BroadcastJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dryRun))
+	assert.Equal(t, service.BroadcastDryRun, dryRun.Status)
+	assert.Equal(t, 3, dryRun.Total)
+	assert.Zero(t, dispatcher.sentCount())
+
+	w = broadcast("maintenance", dto.BroadcastRequest{Message: "Maintenance tonight"})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started service.BroadcastJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+
+	require.Eventually(t, func() bool {
+		return poll(started.ID).Status == service.BroadcastCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	job := poll(started.ID)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, 2, job.Sent)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, "Maintenance tonight", dispatcher.sent[10])
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/broadcasts/missing", nil)
+	r.SetBasicAuth("admin", "admin123")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
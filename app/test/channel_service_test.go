@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChannelResolver knows a fixed set of channels the bot may post in
+type fakeChannelResolver struct {
+	channels map[string]*service.ChannelInfo
+}
+
+func (f *fakeChannelResolver) ResolveChannel(ctx context.Context, channel string) (*service.ChannelInfo, error) {
+	if _, err := service.ParseChannelID(channel); err != nil {
+		return nil, err
+	}
+	info, ok := f.channels[channel]
+	if !ok {
+		return nil, service.ErrChannelCannotPost
+	}
+	return info, nil
+}
+
+type fakeUserLookup struct {
+	service.UserService
+	users map[uuid.UUID]*entity.User
+}
+
+func (f *fakeUserLookup) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+// recordingSubscriptions records the chats that were subscribed
+type recordingSubscriptions struct {
+	service.SubscriptionService
+	subscribed map[int64]string
+}
+
+func (f *recordingSubscriptions) Subscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (*entity.Subscription, error) {
+	f.subscribed[chatID] = notificationTypeCode
+	return &entity.Subscription{ChatID: chatID}, nil
+}
+
+func (f *recordingSubscriptions) Unsubscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string) error {
+	delete(f.subscribed, chatID)
+	return nil
+}
+
+func TestChannelService_SubscribeChannel(t *testing.T) {
+	admin := &entity.User{ID: uuid.New(), TelegramUserID: 1, Role: "admin", ApprovalStatus: "approved"}
+	member := &entity.User{ID: uuid.New(), TelegramUserID: 2, Role: "user", ApprovalStatus: "approved"}
+
+	resolver := &fakeChannelResolver{channels: map[string]*service.ChannelInfo{
+		"@alerts": {ChatID: -1001234, Title: "Alerts", Username: "alerts"},
+	}}
+	users := &fakeUserLookup{users: map[uuid.UUID]*entity.User{admin.ID: admin, member.ID: member}}
+	subscriptions := &recordingSubscriptions{subscribed: make(map[int64]string)}
+	channels := service.NewChannelService(resolver, users, subscriptions)
+	ctx := context.Background()
+
+	subscription, info, err := channels.SubscribeChannel(ctx, admin.ID, "@alerts", "coinbase", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1001234), subscription.ChatID)
+	assert.Equal(t, "Alerts", info.Title)
+	assert.Equal(t, "coinbase", subscriptions.subscribed[-1001234])
+
+	_, _, err = channels.SubscribeChannel(ctx, member.ID, "@alerts", "coinbase", nil)
+	assert.ErrorIs(t, err, service.ErrNotAdmin)
+
+	_, _, err = channels.SubscribeChannel(ctx, admin.ID, "@elsewhere", "coinbase", nil)
+	assert.ErrorIs(t, err, service.ErrChannelCannotPost)
+
+	_, _, err = channels.SubscribeChannel(ctx, admin.ID, "not a channel", "coinbase", nil)
+	assert.ErrorIs(t, err, service.ErrChannelNotFound)
+
+	// Numeric IDs are removed without asking Telegram
+	require.NoError(t, channels.UnsubscribeChannel(ctx, admin.ID, "-1001234", "coinbase"))
+	assert.Empty(t, subscriptions.subscribed)
+}
+
+func TestParseChannelID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  interface{}
+	}{
+		{"-1001234567890", int64(-1001234567890)},
+		{"@my_alerts", "@my_alerts"},
+		{"https://t.me/my_alerts", "@my_alerts"},
+	}
+	for _, tt := range tests {
+		got, err := service.ParseChannelID(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := service.ParseChannelID("my alerts")
+	assert.ErrorIs(t, err, service.ErrChannelNotFound)
+}
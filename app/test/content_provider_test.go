@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentProviderRegistry_RejectsDuplicateCodes(t *testing.T) {
+	registry := service.NewContentProviderRegistry()
+	first := &staticProvider{}
+	require.NoError(t, registry.Register(first))
+
+	err := registry.Register(&staticProvider{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "content provider for 'custom' is already registered")
+
+	// The first registration is kept
+	provider, ok := registry.Get("custom")
+	require.True(t, ok)
+	assert.Same(t, first, provider)
+
+	assert.Error(t, registry.Register(nil))
+}
+
+func TestContentProviderRegistry_Unregistered(t *testing.T) {
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(&staticProvider{}))
+	require.NoError(t, registry.Register(&service.NewsContentProvider{}))
+
+	types := []*entity.NotificationType{
+		{Code: "custom"},
+		{Code: "security"},
+		{Code: "news"},
+		{Code: "maintenance"},
+	}
+	assert.Equal(t, []string{"security", "maintenance"}, registry.Unregistered(types))
+	assert.Equal(t, []string{"custom", "news"}, registry.Codes())
+
+	assert.Empty(t, registry.Unregistered(types[:1]))
+}
+
+func TestContentProviderRegistry_ExternalTypes(t *testing.T) {
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(&staticProvider{}))
+	require.NoError(t, registry.RegisterExternal("security", "maintenance"))
+
+	// A code is either generated by a provider or delivered externally, not both
+	assert.Error(t, registry.RegisterExternal("custom"))
+	require.NoError(t, registry.RegisterExternal("news"))
+	assert.Error(t, registry.Register(&service.NewsContentProvider{}))
+
+	assert.True(t, registry.IsExternal("security"))
+	assert.False(t, registry.Has("security"))
+	assert.True(t, registry.Delivers("security"))
+	assert.True(t, registry.Delivers("custom"))
+	assert.False(t, registry.Delivers("weather"))
+
+	types := []*entity.NotificationType{{Code: "custom"}, {Code: "security"}, {Code: "weather"}}
+	assert.Equal(t, []string{"weather"}, registry.Unregistered(types))
+}
+
+func TestTypesCommand_OffersExternallyDeliveredTypes(t *testing.T) {
+	api := newFakeTelegramAPI()
+	defer api.Close()
+
+	types := &lockedTypeRepository{memoryTypeRepository: &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "security", Name: "Security Alerts", DefaultIntervalMinutes: 1, IsActive: true},
+		{ID: 2, Code: "gold", Name: "Gold", DefaultIntervalMinutes: 30, IsActive: true},
+	}}}
+	telegramBot := service.NewTelegramBotService("test-token", nil, nil,
+		service.NewNotificationTypeService(types, nil), nil, service.NewDefaultContentProviderRegistry(nil, nil), 0,
+		bot.WithServerURL(api.URL), bot.WithSkipGetMe())
+
+	telegramBot.HandleUpdate(context.Background(), nil, &models.Update{CallbackQuery: &models.CallbackQuery{
+		ID:   "callback",
+		From: models.User{ID: 7},
+		Message: models.MaybeInaccessibleMessage{
+			Type:    models.MaybeInaccessibleMessageTypeMessage,
+			Message: &models.Message{ID: 9, Chat: models.Chat{ID: 7, Type: "private"}},
+		},
+		Data: "types:all",
+	}})
+
+	sent := api.sent()
+	require.Len(t, sent, 1)
+
+	// Security alerts come from the detection service and can be subscribed to;
+	// a type nothing delivers cannot
+	assert.Contains(t, sent[0].params["reply_markup"], "subscribe:security")
+	assert.NotContains(t, sent[0].params["reply_markup"], "subscribe:gold")
+	assert.Contains(t, sent[0].params["text"], "Not available yet")
+}
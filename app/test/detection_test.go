@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDelivery "go-messaging/delivery/http"
+	"go-messaging/entity"
+	"go-messaging/model"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDetection(t *testing.T) {
+	message := service.FormatDetection(model.DetectionSummary{
+		Filename:       "invoice.pdf.exe",
+		Classification: "Trojan",
+		RiskLevel:      "Critical",
+		Confidence:     "97%",
+		ActionRequired: "Quarantine the host",
+		KeyFindings:    []string{"Packed executable", "Contacts known C2 server"},
+	})
+
+	assert.True(t, strings.HasPrefix(message, "🔴 CRITICAL risk detection"), message)
+	assert.Contains(t, message, "📄 File: invoice.pdf.exe")
+	assert.Contains(t, message, "• Contacts known C2 server")
+	assert.Contains(t, message, "⚠️ Action required: Quarantine the host")
+
+	// Oversized summaries are cut to fit in one Telegram message
+	long := service.FormatDetection(model.DetectionSummary{RiskLevel: "low", Summary: strings.Repeat("é", model.MAX_MESSAGE_LENGTH)})
+	assert.NoError(t, model.ValidateMessageString(long))
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
+
+func TestDetectionAPI_RoutesByRiskLevel(t *testing.T) {
+	subscription := func(chatID int64, minRisk string) *entity.Subscription {
+		preferences := entity.SubscriptionPreferences{}
+		if minRisk != "" {
+			preferences.Settings = map[string]string{"min_risk": minRisk}
+		}
+		return &entity.Subscription{ID: chatID, ChatID: chatID, Preferences: preferences}
+	}
+	subscriptions := &activeSubscriptionService{active: []*entity.Subscription{
+		subscription(1, "low"), // analysts see everything
+		subscription(2, ""),    // on-call keeps the default
+		subscription(3, "critical"),
+	}}
+	dispatcher := &recordingDispatcher{sent: make(map[int64]string)}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes := &httpDelivery.RouteConfig{
+		Router:           router,
+		DetectionHandler: httpDelivery.NewDetectionHandler(service.NewDetectionService(subscriptions, dispatcher)),
+	}
+	routes.Setup()
+
+	send := func(risk string) (int, service.DispatchResult) {
+		body, _ := json.Marshal(model.DetectionSummary{Filename: "setup.exe", RiskLevel: risk})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/detections", bytes.NewBuffer(body))
+		req.SetBasicAuth("admin", "admin123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var result service.DispatchResult
+		_ = json.Unmarshal(w.Body.Bytes(), &result)
+		return w.Code, result
+	}
+
+	code, _ := send("severe")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, result := send("medium")
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Contains(t, dispatcher.sent[1], "MEDIUM risk")
+	assert.NotContains(t, dispatcher.sent, int64(2))
+
+	_, result = send("high")
+	assert.Equal(t, 2, result.Sent)
+	assert.Contains(t, dispatcher.sent[2], "HIGH risk")
+	assert.NotContains(t, dispatcher.sent, int64(3))
+
+	_, result = send("critical")
+	assert.Equal(t, 3, result.Sent)
+}
+
+func TestDispatchNotification_LeavesTypesWithoutProvider(t *testing.T) {
+	subscriptions := &fakeSubscriptionService{due: newDueSubscriptions(3)}
+
+	dispatcher := service.NewNotificationDispatchService(subscriptions, &fakeLogService{}, &fakeSender{}, service.NewContentProviderRegistry(), nil, service.DispatchConfig{})
+
+	// Detections are sent by their own service, so nothing is claimed
+	result, err := dispatcher.DispatchNotification(context.Background(), service.DetectionNotificationType)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Total)
+	assert.Empty(t, subscriptions.claimed)
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubscriptionService serves a fixed list of due subscriptions. Claims behave
+// like the database: a claimed subscription is not handed out again until released.
+type fakeSubscriptionService struct {
+	service.SubscriptionService
+
+	due          []*entity.Subscription
+	minIntervals map[int]int
+
+	mutex    sync.Mutex
+	claimed  map[int64]bool
+	released map[int64]int
+}
+
+func (f *fakeSubscriptionService) GetDueSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error) {
+	return f.due, nil
+}
+
+func (f *fakeSubscriptionService) GetMinIntervals(ctx context.Context) (map[int]int, error) {
+	return f.minIntervals, nil
+}
+
+func (f *fakeSubscriptionService) ClaimDueSubscriptions(ctx context.Context, notificationTypeCode string) ([]*entity.Subscription, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.claimed == nil {
+		f.claimed = make(map[int64]bool)
+	}
+
+	var claimed []*entity.Subscription
+	for _, subscription := range f.due {
+		if !f.claimed[subscription.ID] {
+			f.claimed[subscription.ID] = true
+			claimed = append(claimed, subscription)
+		}
+	}
+	return claimed, nil
+}
+
+func (f *fakeSubscriptionService) ReleaseClaim(ctx context.Context, subscription *entity.Subscription) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.released == nil {
+		f.released = make(map[int64]int)
+	}
+	delete(f.claimed, subscription.ID)
+	f.released[subscription.ID]++
+	return nil
+}
+
+// fakeLogService records notification log statuses
+type fakeLogService struct {
+	service.NotificationLogService
+
+	mutex    sync.Mutex
+	statuses map[string]int
+}
+
+func (f *fakeLogService) LogNotification(ctx context.Context, subscriptionID int64, message, status string, errorMessage *string) (*entity.NotificationLog, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.statuses == nil {
+		f.statuses = make(map[string]int)
+	}
+	f.statuses[status]++
+	return &entity.NotificationLog{SubscriptionID: &subscriptionID, Message: message, Status: status}, nil
+}
+
+func (f *fakeLogService) LogChatMessage(ctx context.Context, chatID int64, message, status string, errorMessage *string) (*entity.NotificationLog, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.statuses == nil {
+		f.statuses = make(map[string]int)
+	}
+	f.statuses[status]++
+	return &entity.NotificationLog{ChatID: &chatID, Message: message, Status: status}, nil
+}
+
+// fakeSender records sent messages and tracks peak concurrency
+type fakeSender struct {
+	delay    time.Duration
+	failChat int64
+
+	mutex    sync.Mutex
+	sent     map[int64]int
+	inFlight int32
+	peak     int32
+}
+
+func (f *fakeSender) SendMessage(chatID int64, message string) error {
+	current := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if current <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, current) {
+			break
+		}
+	}
+
+	time.Sleep(f.delay)
+
+	if chatID == f.failChat {
+		return fmt.Errorf("chat %d not found", chatID)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.sent == nil {
+		f.sent = make(map[int64]int)
+	}
+	f.sent[chatID]++
+	return nil
+}
+
+func (f *fakeSender) SendMessageWithKeyboard(chatID int64, message string, keyboard model.InlineKeyboardMarkup) error {
+	return f.SendMessage(chatID, message)
+}
+
+func (f *fakeSender) SendMessageWithKeyboardID(chatID int64, message string, keyboard model.InlineKeyboardMarkup) (int, error) {
+	return 0, f.SendMessage(chatID, message)
+}
+
+func (f *fakeSender) SendFormattedMessage(chatID int64, message, parseMode string, keyboard *model.InlineKeyboardMarkup) (int, error) {
+	return 0, f.SendMessage(chatID, message)
+}
+
+func (f *fakeSender) EditMessageWithKeyboard(chatID int64, messageID int, message string, keyboard *model.InlineKeyboardMarkup) error {
+	return nil
+}
+
+func (f *fakeSender) EditMessageKeyboard(chatID int64, messageID int, keyboard *model.InlineKeyboardMarkup) error {
+	return nil
+}
+
+func (f *fakeSender) DeleteMessage(chatID int64, messageID int) error {
+	return nil
+}
+
+func (f *fakeSender) AnswerCallbackQuery(callbackID, text string) error {
+	return nil
+}
+
+// staticProvider returns a fixed message and skips chats listed in skip
+type staticProvider struct {
+	skip map[int64]bool
+}
+
+func (p *staticProvider) Code() string { return "custom" }
+
+func (p *staticProvider) GetContent(ctx context.Context, preferences *entity.SubscriptionPreferences) (string, error) {
+	return "hello", nil
+}
+
+func (p *staticProvider) GetSubscriptionContent(ctx context.Context, subscription *entity.Subscription) (string, error) {
+	if p.skip[subscription.ChatID] {
+		return "", service.Skip("nothing to report")
+	}
+	return "hello", nil
+}
+
+func newDueSubscriptions(n int) []*entity.Subscription {
+	subscriptions := make([]*entity.Subscription, n)
+	for i := range subscriptions {
+		subscriptions[i] = &entity.Subscription{ID: int64(i + 1), ChatID: int64(1000 + i), IsActive: true}
+	}
+	return subscriptions
+}
+
+func TestDispatchNotification_AggregatesResults(t *testing.T) {
+	subscriptions := &fakeSubscriptionService{due: newDueSubscriptions(10)}
+	logs := &fakeLogService{}
+	sender := &fakeSender{delay: 20 * time.Millisecond, failChat: 1003}
+
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(&staticProvider{skip: map[int64]bool{1005: true, 1006: true}}))
+
+	dispatcher := service.NewNotificationDispatchService(subscriptions, logs, sender, registry, nil, service.DispatchConfig{
+		DefaultConcurrency: 3,
+	})
+
+	result, err := dispatcher.DispatchNotification(context.Background(), "custom")
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, result.Total)
+	assert.Equal(t, 7, result.Sent)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 0, result.Cancelled)
+	assert.LessOrEqual(t, atomic.LoadInt32(&sender.peak), int32(3))
+	assert.Equal(t, 2, logs.statuses["skipped"])
+	assert.Equal(t, 1, logs.statuses["failed"])
+
+	// Only the failed send gives its claim back; skips advance with the default policy
+	assert.Equal(t, map[int64]int{4: 1}, subscriptions.released)
+}
+
+func TestDispatchNotification_StopsOnCancel(t *testing.T) {
+	subscriptions := &fakeSubscriptionService{due: newDueSubscriptions(50)}
+	sender := &fakeSender{delay: 50 * time.Millisecond}
+
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(&staticProvider{}))
+
+	dispatcher := service.NewNotificationDispatchService(subscriptions, &fakeLogService{}, sender, registry, nil, service.DispatchConfig{
+		DefaultConcurrency: 2,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+
+	result, err := dispatcher.DispatchNotification(ctx, "custom")
+	require.Error(t, err)
+	assert.Greater(t, result.Cancelled, 0)
+	assert.Equal(t, result.Total, result.Sent+result.Failed+result.Skipped+result.Cancelled)
+	assert.Len(t, subscriptions.released, result.Cancelled)
+}
+
+func TestDispatchNotification_TwoDispatchersNoDuplicates(t *testing.T) {
+	subscriptions := &fakeSubscriptionService{due: newDueSubscriptions(40)}
+	sender := &fakeSender{delay: 5 * time.Millisecond}
+
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(&staticProvider{}))
+
+	config := service.DispatchConfig{DefaultConcurrency: 4}
+	first := service.NewNotificationDispatchService(subscriptions, &fakeLogService{}, sender, registry, nil, config)
+	second := service.NewNotificationDispatchService(subscriptions, &fakeLogService{}, sender, registry, nil, config)
+
+	// Both replicas tick at the same moment
+	var wg sync.WaitGroup
+	results := make([]*service.DispatchResult, 2)
+	for i, dispatcher := range []service.NotificationDispatchService{first, second} {
+		wg.Add(1)
+		go func(i int, dispatcher service.NotificationDispatchService) {
+			defer wg.Done()
+			result, err := dispatcher.DispatchNotification(context.Background(), "custom")
+			require.NoError(t, err)
+			results[i] = result
+		}(i, dispatcher)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 40, results[0].Sent+results[1].Sent)
+	assert.Len(t, sender.sent, 40)
+	for chatID, count := range sender.sent {
+		assert.Equal(t, 1, count, "chat %d received duplicates", chatID)
+	}
+}
+
+// holdingProvider is a staticProvider whose skips are re-checked on every run
+type holdingProvider struct {
+	staticProvider
+}
+
+func (p *holdingProvider) SkipPolicy() service.SkipPolicy { return service.SkipHold }
+
+func TestDispatchNotification_HeldSkipsLogOncePerStreak(t *testing.T) {
+	subscriptions := &fakeSubscriptionService{due: newDueSubscriptions(1)}
+	logs := &fakeLogService{}
+	sender := &fakeSender{}
+	provider := &holdingProvider{staticProvider{skip: map[int64]bool{1000: true}}}
+
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(provider))
+	dispatcher := service.NewNotificationDispatchService(subscriptions, logs, sender, registry, nil, service.DispatchConfig{})
+
+	dispatch := func() *service.DispatchResult {
+		result, err := dispatcher.DispatchNotification(context.Background(), "custom")
+		require.NoError(t, err)
+		return result
+	}
+
+	// Every run re-checks the held subscription, but only the first skip is logged
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, 1, dispatch().Skipped)
+	}
+	assert.Equal(t, 1, logs.statuses["skipped"])
+	assert.Equal(t, 3, subscriptions.released[1])
+
+	// Content ends the streak, so the next skip is logged again
+	provider.skip = nil
+	assert.Equal(t, 1, dispatch().Sent)
+	subscriptions.ReleaseClaim(context.Background(), subscriptions.due[0])
+
+	provider.skip = map[int64]bool{1000: true}
+	assert.Equal(t, 1, dispatch().Skipped)
+	assert.Equal(t, 2, logs.statuses["skipped"])
+}
+
+func TestNewsContentProvider_SkipsWithoutMatchingNews(t *testing.T) {
+	provider := &service.NewsContentProvider{}
+	assert.Equal(t, service.SkipHold, provider.SkipPolicy())
+
+	_, err := provider.GetContent(context.Background(), &entity.SubscriptionPreferences{Keywords: []string{"gardening"}})
+	skip, ok := service.AsSkip(err)
+	require.True(t, ok, "%v", err)
+	assert.Contains(t, skip.Reason, "gardening")
+
+	// Matching keywords and the unfiltered digest both produce content
+	content, err := provider.GetContent(context.Background(), &entity.SubscriptionPreferences{Keywords: []string{"Bitcoin"}})
+	require.NoError(t, err)
+	assert.Contains(t, content, "Bitcoin")
+
+	_, err = provider.GetContent(context.Background(), nil)
+	assert.NoError(t, err)
+}
+
+func TestDispatchNotification_AdvancedSkipsLogOncePerStreak(t *testing.T) {
+	subscriptions := &fakeSubscriptionService{due: newDueSubscriptions(1)}
+	logs := &fakeLogService{}
+	provider := &staticProvider{skip: map[int64]bool{1000: true}}
+
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(provider))
+	dispatcher := service.NewNotificationDispatchService(subscriptions, logs, &fakeSender{}, registry, nil, service.DispatchConfig{})
+
+	// Each interval checks again, as a price alert below its threshold does
+	for i := 0; i < 3; i++ {
+		result, err := dispatcher.DispatchNotification(context.Background(), "custom")
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Skipped)
+		subscriptions.ReleaseClaim(context.Background(), subscriptions.due[0])
+	}
+	assert.Equal(t, 1, logs.statuses["skipped"])
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	httpDelivery "go-messaging/delivery/http"
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *fakeUserLookup) GetUserByTelegramID(ctx context.Context, telegramUserID int64) (*entity.User, error) {
+	for _, user := range f.users {
+		if user.TelegramUserID == telegramUserID {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func TestMessageAPI_SendsThroughOutboxAndLogs(t *testing.T) {
+	userID, pendingID := uuid.New(), uuid.New()
+	users := &fakeUserLookup{users: map[uuid.UUID]*entity.User{
+		userID:    {ID: userID, TelegramUserID: 42, ApprovalStatus: "approved"},
+		pendingID: {ID: pendingID, TelegramUserID: 43, ApprovalStatus: "pending"},
+	}}
+	repo := newMemoryOutboxRepository()
+	sender := &fakeSender{}
+	logs := &fakeLogService{}
+	outbox := newTestOutbox(repo, sender, logs)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes := &httpDelivery.RouteConfig{
+		Router:         router,
+		MessageHandler: httpDelivery.NewMessageHandler(service.NewDirectMessageService(users, outbox)),
+	}
+	routes.Setup()
+
+	request := func(method, path string, body any, authenticated bool) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+		if authenticated {
+			req.SetBasicAuth("admin", "admin123")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send := func(req dto.SendMessageRequest) *httptest.ResponseRecorder {
+		return request(http.MethodPost, "/api/v1/messages", req, true)
+	}
+	telegramUserID, chatID := int64(42), int64(-100)
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodPost, "/api/v1/messages", dto.SendMessageRequest{ChatID: &chatID, Text: "hi"}, false).Code)
+
+	// Requests Telegram would reject are refused up front
+	assert.Equal(t, http.StatusBadRequest, send(dto.SendMessageRequest{ChatID: &chatID, TelegramUserID: &telegramUserID, Text: "hi"}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(dto.SendMessageRequest{ChatID: &chatID, Text: "hi", ParseMode: "BBCode"}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(dto.SendMessageRequest{ChatID: &chatID, Text: "hi", Buttons: entity.InlineButtons{
+		{{Text: "Open", URL: "javascript:alert(1)"}},
+	}}).Code)
+	unknown := int64(7)
+	assert.Equal(t, http.StatusNotFound, send(dto.SendMessageRequest{TelegramUserID: &unknown, Text: "hi"}).Code)
+
+	// Users are only messaged once approved
+	pending := int64(43)
+	assert.Equal(t, http.StatusForbidden, send(dto.SendMessageRequest{TelegramUserID: &pending, Text: "hi"}).Code)
+	assert.Equal(t, http.StatusForbidden, send(dto.SendMessageRequest{UserID: &pendingID, Text: "hi"}).Code)
+	assert.Empty(t, repo.messages)
+
+	w := send(dto.SendMessageRequest{UserID: &userID, Text: "*Deploy finished*", ParseMode: "MarkdownV2", Buttons: entity.InlineButtons{
+		{{Text: "Open", URL: "https://example.com/deploys/1"}},
+	}})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var queued dto.MessageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &queued))
+	assert.Equal(t, int64(42), queued.ChatID)
+	assert.Equal(t, entity.OutboundStatusPending, queued.Status)
+	assert.Equal(t, "MarkdownV2", repo.messages[queued.ID].ParseMode)
+
+	_, err := outbox.ProcessDue(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sender.sent[42])
+	assert.Equal(t, 1, logs.statuses["sent"])
+
+	w = request(http.MethodGet, "/api/v1/messages/"+strconv.FormatInt(queued.ID, 10), nil, true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var delivered dto.MessageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &delivered))
+	assert.Equal(t, entity.OutboundStatusSent, delivered.Status)
+	assert.NotNil(t, delivered.SentAt)
+
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/api/v1/messages/99", nil, true).Code)
+}
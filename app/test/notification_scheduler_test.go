@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/internal/scheduler"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotificationTypeService serves a mutable list of active types
+type fakeNotificationTypeService struct {
+	service.NotificationTypeService
+
+	mutex sync.Mutex
+	types []*entity.NotificationType
+}
+
+func (f *fakeNotificationTypeService) GetActiveTypes(ctx context.Context) ([]*entity.NotificationType, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var active []*entity.NotificationType
+	for _, nt := range f.types {
+		if nt.IsActive {
+			active = append(active, nt)
+		}
+	}
+	return active, nil
+}
+
+// externalDispatcher dispatches every type except the externally delivered ones
+type externalDispatcher struct {
+	service.NotificationDispatchService
+	external map[string]bool
+}
+
+func (d *externalDispatcher) Dispatches(notificationTypeCode string) bool {
+	return !d.external[notificationTypeCode]
+}
+
+func TestNotificationScheduler_ReloadFollowsDatabase(t *testing.T) {
+	types := &fakeNotificationTypeService{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", DefaultIntervalMinutes: 60, IsActive: true},
+		{ID: 2, Code: "news", DefaultIntervalMinutes: 120, IsActive: true},
+		{ID: 3, Code: "weather", DefaultIntervalMinutes: 30, IsActive: false},
+		{ID: 5, Code: "security", DefaultIntervalMinutes: 1, IsActive: true},
+	}}
+	subscriptions := &fakeSubscriptionService{minIntervals: map[int]int{1: 5, 2: 240}}
+
+	// Security alerts are sent by the detection service, so they never tick
+	ns := scheduler.NewNotificationScheduler(&externalDispatcher{external: map[string]bool{"security": true}}, types, subscriptions)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, ns.Reload(ctx))
+	assert.Equal(t, map[string]time.Duration{
+		"coinbase": 5 * time.Minute,   // a subscriber wants updates every 5 minutes
+		"news":     120 * time.Minute, // longer subscriber intervals don't slow the type down
+	}, ns.Schedule())
+
+	// Types created or deactivated at runtime are picked up on reload
+	types.mutex.Lock()
+	types.types[1].IsActive = false
+	types.types = append(types.types, &entity.NotificationType{ID: 4, Code: "custom", DefaultIntervalMinutes: 15, IsActive: true})
+	types.mutex.Unlock()
+
+	require.NoError(t, ns.Reload(ctx))
+	assert.Equal(t, map[string]time.Duration{
+		"coinbase": 5 * time.Minute,
+		"custom":   15 * time.Minute,
+	}, ns.Schedule())
+}
+
+// lockedTypeRepository guards a memoryTypeRepository shared with a running scheduler
+type lockedTypeRepository struct {
+	*memoryTypeRepository
+	mutex sync.Mutex
+}
+
+func (r *lockedTypeRepository) GetActive(ctx context.Context) ([]*entity.NotificationType, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var active []*entity.NotificationType
+	for _, notificationType := range r.types {
+		if notificationType.IsActive {
+			copied := *notificationType
+			active = append(active, &copied)
+		}
+	}
+	return active, nil
+}
+
+func (r *lockedTypeRepository) GetByCode(ctx context.Context, code string) (*entity.NotificationType, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.memoryTypeRepository.GetByCode(ctx, code)
+}
+
+func (r *lockedTypeRepository) Create(ctx context.Context, notificationType *entity.NotificationType) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.memoryTypeRepository.Create(ctx, notificationType)
+}
+
+func (r *lockedTypeRepository) Update(ctx context.Context, notificationType *entity.NotificationType) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.memoryTypeRepository.Update(ctx, notificationType)
+}
+
+func TestNotificationScheduler_ReloadsWhenTypesChange(t *testing.T) {
+	types := &lockedTypeRepository{memoryTypeRepository: &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", Name: "Coinbase Alerts", DefaultIntervalMinutes: 60, IsActive: true},
+	}}}
+	typeService := service.NewNotificationTypeService(types, nil)
+
+	// Wired as in main: without a type change nothing would reload for an hour
+	ns := scheduler.NewNotificationScheduler(&externalDispatcher{}, typeService, &fakeSubscriptionService{})
+	ns.SetReloadInterval(time.Hour)
+	typeService.SetChangeListener(ns)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ns.Start(ctx)
+
+	hasSchedule := func(expected map[string]time.Duration) func() bool {
+		return func() bool { return assert.ObjectsAreEqual(expected, ns.Schedule()) }
+	}
+	require.Eventually(t, hasSchedule(map[string]time.Duration{"coinbase": time.Hour}), time.Second, 10*time.Millisecond)
+
+	_, err := typeService.CreateType(ctx, "gold", "Gold", nil, 30, entity.PreferenceSchema{})
+	require.NoError(t, err)
+	assert.Eventually(t, hasSchedule(map[string]time.Duration{"coinbase": time.Hour, "gold": 30 * time.Minute}), time.Second, 10*time.Millisecond)
+
+	require.NoError(t, typeService.DeactivateType(ctx, "coinbase"))
+	assert.Eventually(t, hasSchedule(map[string]time.Duration{"gold": 30 * time.Minute}), time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-messaging/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboundLimiter_SpacesMessagesPerChat(t *testing.T) {
+	limiter := model.NewOutboundLimiterWithLimits(0, 50*time.Millisecond, 150*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Wait(ctx, 1))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// Another chat is not held back by the first one
+	start = time.Now()
+	require.NoError(t, limiter.Wait(ctx, 2))
+	assert.Less(t, time.Since(start), 20*time.Millisecond)
+
+	// Groups get the slower group interval
+	start = time.Now()
+	require.NoError(t, limiter.Wait(ctx, -100))
+	require.NoError(t, limiter.Wait(ctx, -100))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestOutboundLimiter_PauseHoldsChat(t *testing.T) {
+	limiter := model.NewOutboundLimiterWithLimits(0, time.Millisecond, time.Millisecond)
+	limiter.Pause(1, 100*time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background(), 1))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// Cancelling the context aborts the wait instead of dropping into a send
+	limiter.Pause(1, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, limiter.Wait(ctx, 1))
+}
+
+func TestOutboundLimiter_CancelledWaitReturnsSlot(t *testing.T) {
+	limiter := model.NewOutboundLimiterWithLimits(0, 100*time.Millisecond, 100*time.Millisecond)
+	require.NoError(t, limiter.Wait(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, limiter.Wait(ctx, 1))
+
+	// The next send takes the abandoned slot instead of queueing behind it
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background(), 1))
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestOutboundLimiter_PausedUntil(t *testing.T) {
+	limiter := model.NewOutboundLimiterWithLimits(0, time.Millisecond, time.Millisecond)
+	assert.True(t, limiter.PausedUntil(1).IsZero())
+
+	limiter.Pause(1, time.Minute)
+	assert.True(t, limiter.PausedUntil(1).After(time.Now().Add(59*time.Second)))
+	assert.True(t, limiter.PausedUntil(2).IsZero())
+}
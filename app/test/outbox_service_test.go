@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/internal/scheduler"
+	"go-messaging/model"
+	"go-messaging/service"
+
+	"github.com/go-telegram/bot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryOutboxRepository keeps outbound messages in memory
+type memoryOutboxRepository struct {
+	mutex    sync.Mutex
+	nextID   int64
+	messages map[int64]*entity.OutboundMessage
+}
+
+func newMemoryOutboxRepository() *memoryOutboxRepository {
+	return &memoryOutboxRepository{messages: make(map[int64]*entity.OutboundMessage)}
+}
+
+func (r *memoryOutboxRepository) Create(ctx context.Context, message *entity.OutboundMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.nextID++
+	message.ID = r.nextID
+	copied := *message
+	r.messages[message.ID] = &copied
+	return nil
+}
+
+func (r *memoryOutboxRepository) GetByID(ctx context.Context, id int64) (*entity.OutboundMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	message, ok := r.messages[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *message
+	return &copied, nil
+}
+
+func (r *memoryOutboxRepository) ClaimDue(ctx context.Context, limit int) ([]*entity.OutboundMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var due []*entity.OutboundMessage
+	for _, message := range r.messages {
+		if message.Status == entity.OutboundStatusPending && !message.NextAttemptAt.After(time.Now()) {
+			due = append(due, message)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*entity.OutboundMessage, len(due))
+	now := time.Now()
+	for i, message := range due {
+		message.Status = entity.OutboundStatusSending
+		message.LockedAt = &now
+		copied := *message
+		claimed[i] = &copied
+	}
+	return claimed, nil
+}
+
+func (r *memoryOutboxRepository) Update(ctx context.Context, message *entity.OutboundMessage) error {
+	// Like the database, refuse writes once the context is done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	copied := *message
+	r.messages[message.ID] = &copied
+	return nil
+}
+
+func (r *memoryOutboxRepository) ListByStatus(ctx context.Context, status string, offset, limit int) ([]*entity.OutboundMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var result []*entity.OutboundMessage
+	for _, message := range r.messages {
+		if message.Status == status {
+			copied := *message
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOutboxRepository) CountByStatus(ctx context.Context, status string) (int64, error) {
+	messages, _ := r.ListByStatus(ctx, status, 0, 0)
+	return int64(len(messages)), nil
+}
+
+func (r *memoryOutboxRepository) ReleaseStale(ctx context.Context, duration time.Duration) (int, error) {
+	return 0, nil
+}
+
+// scriptedSender returns queued errors per chat, then succeeds
+type scriptedSender struct {
+	fakeSender
+	errs map[int64][]error
+}
+
+// cancellingSender cancels the run while a message is being sent, like a shutdown
+type cancellingSender struct {
+	fakeSender
+	cancel context.CancelFunc
+}
+
+func (s *cancellingSender) SendFormattedMessage(chatID int64, message, parseMode string, keyboard *model.InlineKeyboardMarkup) (int, error) {
+	s.cancel()
+	return s.fakeSender.SendFormattedMessage(chatID, message, parseMode, keyboard)
+}
+
+func (s *scriptedSender) SendFormattedMessage(chatID int64, message, parseMode string, keyboard *model.InlineKeyboardMarkup) (int, error) {
+	s.mutex.Lock()
+	if queued := s.errs[chatID]; len(queued) > 0 {
+		s.errs[chatID] = queued[1:]
+		s.mutex.Unlock()
+		return 0, queued[0]
+	}
+	s.mutex.Unlock()
+	return s.fakeSender.SendFormattedMessage(chatID, message, parseMode, keyboard)
+}
+
+func newTestOutbox(repo *memoryOutboxRepository, sender service.TelegramNotificationSender, logs *fakeLogService) service.OutboxService {
+	return service.NewOutboxService(repo, logs, sender, service.OutboxConfig{
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+	})
+}
+
+func TestOutbox_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	logs := &fakeLogService{}
+	failure := errors.New("connection reset")
+	sender := &scriptedSender{errs: map[int64][]error{42: {failure, failure, failure}}}
+	outbox := newTestOutbox(repo, sender, logs)
+	ctx := context.Background()
+
+	subscriptionID := int64(7)
+	queued, err := outbox.Enqueue(ctx, &subscriptionID, 42, "hello")
+	require.NoError(t, err)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		count, err := outbox.ProcessDue(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		message, _ := repo.GetByID(ctx, queued.ID)
+		assert.Equal(t, attempt, message.Attempts)
+
+		if attempt < 3 {
+			assert.Equal(t, entity.OutboundStatusPending, message.Status)
+			assert.True(t, message.NextAttemptAt.After(time.Now().Add(time.Duration(attempt)*time.Minute-time.Second)))
+
+			// Nothing is due until the backoff has passed
+			count, err = outbox.ProcessDue(ctx, 10)
+			require.NoError(t, err)
+			assert.Equal(t, 0, count)
+
+			message.NextAttemptAt = time.Now()
+			require.NoError(t, repo.Update(ctx, message))
+		}
+	}
+
+	dead, total, err := outbox.GetDeadLetters(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "connection reset", *dead[0].LastError)
+	assert.Equal(t, 1, logs.statuses["failed"])
+
+	// Requeueing gives the message a fresh attempt budget and it is delivered
+	require.NoError(t, outbox.Requeue(ctx, queued.ID))
+	_, err = outbox.ProcessDue(ctx, 10)
+	require.NoError(t, err)
+
+	message, _ := repo.GetByID(ctx, queued.ID)
+	assert.Equal(t, entity.OutboundStatusSent, message.Status)
+	assert.Equal(t, 1, sender.sent[42])
+	assert.Equal(t, 1, logs.statuses["sent"])
+}
+
+func TestOutbox_HonoursRetryAfter(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	sender := &scriptedSender{errs: map[int64][]error{1: {&bot.TooManyRequestsError{Message: "too many requests", RetryAfter: 30}}}}
+	outbox := newTestOutbox(repo, sender, &fakeLogService{})
+	ctx := context.Background()
+
+	first, _ := outbox.Enqueue(ctx, nil, 1, "one")
+	second, _ := outbox.Enqueue(ctx, nil, 2, "two")
+
+	_, err := outbox.ProcessDue(ctx, 10)
+	require.NoError(t, err)
+
+	// The rate-limited message and the rest of the batch wait for retry_after without using an attempt
+	for _, id := range []int64{first.ID, second.ID} {
+		message, _ := repo.GetByID(ctx, id)
+		assert.Equal(t, entity.OutboundStatusPending, message.Status)
+		assert.Equal(t, 0, message.Attempts)
+		assert.True(t, message.NextAttemptAt.After(time.Now().Add(29*time.Second)))
+	}
+	assert.Empty(t, sender.sent)
+}
+
+func TestOutbox_PausedChatWaitsWithoutHoldingBatch(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	until := time.Now().Add(time.Minute)
+	sender := &scriptedSender{errs: map[int64][]error{1: {&model.ChatPausedError{ChatID: 1, Until: until}}}}
+	outbox := newTestOutbox(repo, sender, &fakeLogService{})
+	ctx := context.Background()
+
+	paused, _ := outbox.Enqueue(ctx, nil, 1, "one")
+	other, _ := outbox.Enqueue(ctx, nil, 2, "two")
+
+	_, err := outbox.ProcessDue(ctx, 10)
+	require.NoError(t, err)
+
+	// Only the paused chat's message waits, and it keeps its attempt budget
+	message, _ := repo.GetByID(ctx, paused.ID)
+	assert.Equal(t, entity.OutboundStatusPending, message.Status)
+	assert.Equal(t, 0, message.Attempts)
+	assert.True(t, message.NextAttemptAt.Equal(until))
+
+	message, _ = repo.GetByID(ctx, other.ID)
+	assert.Equal(t, entity.OutboundStatusSent, message.Status)
+}
+
+func TestOutbox_PermanentErrorDeadLettersImmediately(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	sender := &scriptedSender{errs: map[int64][]error{1: {bot.ErrorForbidden}}}
+	outbox := newTestOutbox(repo, sender, &fakeLogService{})
+	ctx := context.Background()
+
+	queued, _ := outbox.Enqueue(ctx, nil, 1, "blocked")
+	_, err := outbox.ProcessDue(ctx, 10)
+	require.NoError(t, err)
+
+	message, _ := repo.GetByID(ctx, queued.ID)
+	assert.Equal(t, entity.OutboundStatusDead, message.Status)
+	assert.Equal(t, 1, message.Attempts)
+}
+
+func TestOutbox_RecordsOutcomeDuringShutdown(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logs := &fakeLogService{}
+	outbox := newTestOutbox(repo, &cancellingSender{cancel: cancel}, logs)
+
+	queued, _ := outbox.Enqueue(ctx, nil, 1, "hello")
+	_, err := outbox.ProcessDue(ctx, 10)
+	require.NoError(t, err)
+
+	// The message went out before the cancel took effect, so it must not be left in sending
+	message, _ := repo.GetByID(context.Background(), queued.ID)
+	assert.Equal(t, entity.OutboundStatusSent, message.Status)
+	assert.Equal(t, 1, logs.statuses["sent"])
+}
+
+// recoveringOutbox counts stale recoveries and has nothing to send
+type recoveringOutbox struct {
+	service.OutboxService
+	recoveries atomic.Int32
+}
+
+func (o *recoveringOutbox) RecoverStale(ctx context.Context) (int, error) {
+	o.recoveries.Add(1)
+	return 0, nil
+}
+
+func (o *recoveringOutbox) ProcessDue(ctx context.Context, batchSize int) (int, error) {
+	return 0, nil
+}
+
+func TestOutboxScheduler_RecoversStaleOnEveryTick(t *testing.T) {
+	outbox := &recoveringOutbox{}
+	outboxScheduler := scheduler.NewOutboxScheduler(outbox, 10*time.Millisecond, 10)
+	outboxScheduler.Start(context.Background())
+	defer outboxScheduler.Stop()
+
+	// Messages stranded by another instance are picked up while this one runs
+	assert.Eventually(t, func() bool { return outbox.recoveries.Load() >= 3 }, time.Second, 5*time.Millisecond)
+}
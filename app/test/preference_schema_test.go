@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go-messaging/entity"
+	"go-messaging/internal/price/pricetest"
+	"go-messaging/repository"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPreferenceSchema_FillsDefaults(t *testing.T) {
+	priceAlert := testNotificationType(t, "price_alert", priceAlertSchema)
+
+	prefs := entity.SubscriptionPreferences{Interval: 5, Currency: "eth"}
+	require.NoError(t, service.ApplyPreferenceSchema(priceAlert.PreferenceSchema, &prefs))
+
+	assert.Equal(t, "ETH", prefs.Currency)
+	assert.Equal(t, 50000.0, prefs.Threshold)
+	assert.Equal(t, "above", prefs.Direction)
+	assert.Equal(t, 5, prefs.Interval)
+}
+
+func TestApplyPreferenceSchema_Rejects(t *testing.T) {
+	priceAlert := testNotificationType(t, "price_alert", priceAlertSchema)
+	required := testNotificationType(t, "weather", `{"properties": {"location": {"type": "string"}}, "required": ["location"]}`)
+
+	tests := []struct {
+		name     string
+		schema   entity.PreferenceSchema
+		prefs    entity.SubscriptionPreferences
+		problems []string
+	}{
+		{"unknown key", priceAlert.PreferenceSchema, entity.SubscriptionPreferences{Keywords: []string{"go"}},
+			[]string{"keywords is not a setting of this notification type"}},
+		{"unknown setting", priceAlert.PreferenceSchema, entity.SubscriptionPreferences{Settings: map[string]string{"colour": "red"}},
+			[]string{"colour is not a setting of this notification type"}},
+		{"below minimum", priceAlert.PreferenceSchema, entity.SubscriptionPreferences{Threshold: -1},
+			[]string{"threshold must be at least 0.01"}},
+		{"enum", priceAlert.PreferenceSchema, entity.SubscriptionPreferences{Direction: "sideways"},
+			[]string{"direction must be one of above, below, both"}},
+		{"missing required", required.PreferenceSchema, entity.SubscriptionPreferences{},
+			[]string{"location is required"}},
+		// interval bounds hold even for types that do not declare interval
+		{"interval bound", required.PreferenceSchema, entity.SubscriptionPreferences{Interval: -5, Settings: map[string]string{"location": "Berlin"}},
+			[]string{"interval must be at least 1"}},
+		{"cron", entity.PreferenceSchema{}, entity.SubscriptionPreferences{Schedule: "every day"},
+			nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := tt.prefs
+			err := service.ApplyPreferenceSchema(tt.schema, &prefs)
+			require.Error(t, err)
+
+			var preferenceErr *service.PreferenceError
+			require.True(t, errors.As(err, &preferenceErr))
+			if tt.problems != nil {
+				assert.Equal(t, tt.problems, preferenceErr.Problems)
+			}
+		})
+	}
+}
+
+func TestApplyPreferenceSchema_SettingsValues(t *testing.T) {
+	schema := testNotificationType(t, "custom", `{"properties": {
+		"limit": {"type": "integer", "minimum": 1, "default": 3},
+		"tags": {"type": "array"}
+	}}`).PreferenceSchema
+
+	prefs := entity.SubscriptionPreferences{Settings: map[string]string{"tags": "a,b"}}
+	require.NoError(t, service.ApplyPreferenceSchema(schema, &prefs))
+	assert.Equal(t, map[string]string{"limit": "3", "tags": "a,b"}, prefs.Settings)
+
+	prefs = entity.SubscriptionPreferences{Settings: map[string]string{"limit": "2.5"}}
+	assert.Error(t, service.ApplyPreferenceSchema(schema, &prefs))
+}
+
+func TestValidatePreferenceSchema(t *testing.T) {
+	require.NoError(t, service.ValidatePreferenceSchema(testNotificationType(t, "price_alert", priceAlertSchema).PreferenceSchema))
+	require.NoError(t, service.ValidatePreferenceSchema(entity.PreferenceSchema{Required: []string{"interval"}}))
+
+	tests := []struct {
+		name    string
+		schema  string
+		problem string
+	}{
+		{"unknown type", `{"properties": {"enabled": {"type": "bool"}}}`,
+			`enabled has unknown type "bool"`},
+		{"unknown format", `{"properties": {"at": {"type": "string", "format": "price"}}}`,
+			`at has unknown format "price" for type string`},
+		{"required not declared", `{"properties": {"location": {"type": "string"}}, "required": ["city"]}`,
+			"required key city is not a property"},
+		{"bounds", `{"properties": {"limit": {"type": "integer", "minimum": 10, "maximum": 5}}}`,
+			"limit has minimum 10 above maximum 5"},
+		{"default below minimum", `{"properties": {"limit": {"type": "integer", "minimum": 1, "default": 0}}}`,
+			"default of limit: limit must be at least 1"},
+		{"default outside enum", `{"properties": {"level": {"type": "string", "enum": ["low", "high"], "default": "medium"}}}`,
+			"default of level: level must be one of low, high"},
+		{"enum on number", `{"properties": {"limit": {"type": "number", "enum": ["1"]}}}`,
+			"limit can only list enum values for type string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidatePreferenceSchema(testNotificationType(t, "custom", tt.schema).PreferenceSchema)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, service.ErrInvalidPreferenceSchema))
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+// storedSubscriptionRepository holds a single subscription
+type storedSubscriptionRepository struct {
+	repository.SubscriptionRepository
+	subscription *entity.Subscription
+}
+
+func (r *storedSubscriptionRepository) GetByID(ctx context.Context, id int64) (*entity.Subscription, error) {
+	return r.subscription, nil
+}
+
+func (r *storedSubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) error {
+	r.subscription = subscription
+	return nil
+}
+
+func TestSubscriptionService_ChecksCurrencyWithPriceSource(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"BTC-USD": 64250.5, "ETH-EUR": 2900})
+	defer server.Close()
+
+	priceAlert := testNotificationType(t, "price_alert", priceAlertSchema)
+	subscriptions := &storedSubscriptionRepository{subscription: &entity.Subscription{ID: 1, NotificationType: *priceAlert}}
+	subscriptionService := service.NewSubscriptionService(subscriptions, nil, nil, nil, newTestPriceClient(server))
+	ctx := context.Background()
+
+	_, err := subscriptionService.UpdateSubscriptionPreferences(ctx, 1, &entity.SubscriptionPreferences{Currency: "xyz", Threshold: 10})
+	var preferenceErr *service.PreferenceError
+	require.True(t, errors.As(err, &preferenceErr), "%v", err)
+	assert.Contains(t, err.Error(), `unsupported currency "XYZ", no XYZ-USD price is available`)
+
+	// The pair is checked in the subscription's quote currency
+	updated, err := subscriptionService.UpdateSubscriptionPreferences(ctx, 1, &entity.SubscriptionPreferences{
+		Currency: "eth", Threshold: 10, Settings: map[string]string{"quote": "EUR"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", updated.Preferences.Currency)
+
+	// An unreachable price source does not block saving
+	server.SetFailure("BTC-USD", http.StatusServiceUnavailable, "")
+	_, err = subscriptionService.UpdateSubscriptionPreferences(ctx, 1, &entity.SubscriptionPreferences{Currency: "btc", Threshold: 10})
+	assert.NoError(t, err)
+}
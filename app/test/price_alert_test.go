@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go-messaging/entity"
+	"go-messaging/repository"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fixedPriceClient quotes whatever price the test sets
+type fixedPriceClient struct {
+	price float64
+}
+
+func (c *fixedPriceClient) GetSpotPrice(ctx context.Context, base, quote string) (float64, error) {
+	return c.price, nil
+}
+
+// memoryAlertStates keeps price alert states in memory
+type memoryAlertStates struct {
+	repository.PriceAlertStateRepository
+	states map[int64]entity.PriceAlertState
+}
+
+func (r *memoryAlertStates) GetBySubscriptionID(ctx context.Context, subscriptionID int64) (*entity.PriceAlertState, error) {
+	state, ok := r.states[subscriptionID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &state, nil
+}
+
+func (r *memoryAlertStates) Save(ctx context.Context, state *entity.PriceAlertState) error {
+	r.states[state.SubscriptionID] = *state
+	return nil
+}
+
+func TestPriceAlert_FailedSendFiresAgain(t *testing.T) {
+	prices := &fixedPriceClient{price: 49000}
+	states := &memoryAlertStates{states: make(map[int64]entity.PriceAlertState)}
+	registry := service.NewContentProviderRegistry()
+	require.NoError(t, registry.Register(service.NewPriceAlertContentProvider(prices, states)))
+
+	subscription := &entity.Subscription{ID: 1, ChatID: 1000, IsActive: true, Preferences: entity.SubscriptionPreferences{Threshold: 50000}}
+	sender := &fakeSender{}
+	logs := &fakeLogService{}
+	dispatch := func() *service.DispatchResult {
+		subscriptions := &fakeSubscriptionService{due: []*entity.Subscription{subscription}}
+		dispatcher := service.NewNotificationDispatchService(subscriptions, logs, sender, registry, nil, service.DispatchConfig{})
+		result, err := dispatcher.DispatchNotification(context.Background(), "price_alert")
+		require.NoError(t, err)
+		return result
+	}
+
+	// The first run records a baseline below the threshold
+	assert.Equal(t, 1, dispatch().Skipped)
+	assert.Equal(t, service.PriceAlertBelow, states.states[1].State)
+
+	// The alert fires but cannot be sent, so the crossing is not recorded
+	prices.price = 51000
+	sender.failChat = 1000
+	assert.Equal(t, 1, dispatch().Failed)
+	assert.Equal(t, service.PriceAlertBelow, states.states[1].State)
+
+	// The next run fires again and records the crossing once it is sent
+	sender.failChat = 0
+	assert.Equal(t, 1, dispatch().Sent)
+	assert.Equal(t, service.PriceAlertAbove, states.states[1].State)
+	assert.Equal(t, 1, sender.sent[1000])
+
+	// Having crossed, the price staying above does not fire again
+	assert.Equal(t, 1, dispatch().Skipped)
+}
+
+func TestEvaluatePriceCrossing(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  string
+		price     float64
+		band      float64
+		direction string
+		wantState string
+		wantFired bool
+	}{
+		{"first observation records baseline above", "", 51000, 0, service.PriceAlertAbove, service.PriceAlertAbove, false},
+		{"first observation records baseline below", "", 49000, 0, service.PriceAlertAbove, service.PriceAlertBelow, false},
+		{"crossing up fires for above", service.PriceAlertBelow, 50000, 0, service.PriceAlertAbove, service.PriceAlertAbove, true},
+		{"staying above does not re-fire", service.PriceAlertAbove, 52000, 0, service.PriceAlertAbove, service.PriceAlertAbove, false},
+		{"crossing down is silent for above", service.PriceAlertAbove, 49000, 0, service.PriceAlertAbove, service.PriceAlertBelow, false},
+		{"crossing down fires for below", service.PriceAlertAbove, 49000, 0, service.PriceAlertBelow, service.PriceAlertBelow, true},
+		{"crossing up fires for both", service.PriceAlertBelow, 50500, 0, service.PriceAlertBoth, service.PriceAlertAbove, true},
+		{"crossing down fires for both", service.PriceAlertAbove, 49500, 0, service.PriceAlertBoth, service.PriceAlertBelow, true},
+		{"dip inside band does not re-arm", service.PriceAlertAbove, 49600, 500, service.PriceAlertAbove, service.PriceAlertAbove, false},
+		{"dip past band re-arms", service.PriceAlertAbove, 49400, 500, service.PriceAlertAbove, service.PriceAlertBelow, false},
+		{"rise inside band does not re-arm below", service.PriceAlertBelow, 50400, 500, service.PriceAlertBelow, service.PriceAlertBelow, false},
+		{"rise past band re-arms below", service.PriceAlertBelow, 50500, 500, service.PriceAlertBelow, service.PriceAlertAbove, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, fired := service.EvaluatePriceCrossing(tt.previous, tt.price, 50000, tt.band, tt.direction)
+			assert.Equal(t, tt.wantState, state)
+			assert.Equal(t, tt.wantFired, fired)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/internal/price"
+	"go-messaging/internal/price/pricetest"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPriceClient(server *pricetest.Server) *price.CachingClient {
+	return price.NewCachingClient(price.NewCoinbaseClient(price.CoinbaseConfig{
+		BaseURL: server.URL,
+		Timeout: 2 * time.Second,
+	}))
+}
+
+func TestCoinbaseClient_GetSpotPrice(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"BTC-USD": 64250.5})
+	defer server.Close()
+
+	client := newTestPriceClient(server)
+
+	amount, err := client.GetSpotPrice(context.Background(), "btc", "usd")
+	require.NoError(t, err)
+	assert.Equal(t, 64250.5, amount)
+}
+
+func TestCoinbaseClient_UnsupportedPair(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"BTC-USD": 64250.5})
+	defer server.Close()
+
+	client := newTestPriceClient(server)
+
+	_, err := client.GetSpotPrice(context.Background(), "DOGE", "USD")
+	require.Error(t, err)
+	assert.True(t, price.IsUnsupportedPair(err))
+}
+
+func TestCoinbaseClient_ChecksStatusBeforeDecoding(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"BTC-USD": 64250.5})
+	defer server.Close()
+
+	client := newTestPriceClient(server)
+
+	// Proxies answer with HTML; the status decides the error, not the body
+	server.SetFailure("BTC-USD", http.StatusBadGateway, "<html>Bad Gateway</html>")
+	_, err := client.GetSpotPrice(context.Background(), "BTC", "USD")
+	require.Error(t, err)
+	assert.False(t, price.IsUnsupportedPair(err))
+	assert.Contains(t, err.Error(), "status 502: Bad Gateway")
+
+	server.SetFailure("BTC-USD", http.StatusNotFound, "not found")
+	_, err = client.GetSpotPrice(context.Background(), "BTC", "USD")
+	assert.True(t, price.IsUnsupportedPair(err), "%v", err)
+}
+
+func TestCachingClient_FetchesOncePerRun(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"ETH-USD": 3100})
+	defer server.Close()
+
+	client := newTestPriceClient(server)
+	ctx := price.WithRunCache(context.Background())
+
+	for i := 0; i < 3; i++ {
+		amount, err := client.GetSpotPrice(ctx, "ETH", "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 3100.0, amount)
+	}
+	assert.Equal(t, 1, server.Requests("ETH-USD"))
+
+	// A new run sees fresh prices
+	server.SetPrice("ETH-USD", 3200)
+	amount, err := client.GetSpotPrice(price.WithRunCache(context.Background()), "ETH", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, 3200.0, amount)
+	assert.Equal(t, 2, server.Requests("ETH-USD"))
+}
+
+func TestCoinbaseContentProvider_UsesPriceClient(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"ETH-USD": 3100})
+	defer server.Close()
+
+	registry := service.NewDefaultContentProviderRegistry(newTestPriceClient(server), nil)
+	provider, ok := registry.Get("coinbase")
+	require.True(t, ok)
+
+	content, err := provider.GetContent(context.Background(), &entity.SubscriptionPreferences{Currency: "eth"})
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(content, "$3100.00"), content)
+}
+
+func TestCachingClient_FetchesPairsIndependently(t *testing.T) {
+	server := pricetest.NewServer(map[string]float64{"ETH-USD": 3100, "BTC-USD": 64250.5})
+	defer server.Close()
+	server.SetDelay("ETH-USD", 300*time.Millisecond)
+
+	client := newTestPriceClient(server)
+	ctx := price.WithRunCache(context.Background())
+
+	// Concurrent callers for the slow pair share one request
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			amount, err := client.GetSpotPrice(ctx, "ETH", "USD")
+			assert.NoError(t, err)
+			assert.Equal(t, 3100.0, amount)
+		}()
+	}
+
+	// Another pair is not held up by the slow fetch
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	_, err := client.GetSpotPrice(ctx, "BTC", "USD")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+
+	wg.Wait()
+	assert.Equal(t, 1, server.Requests("ETH-USD"))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"go-messaging/internal/schedule"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron_Next(t *testing.T) {
+	berlin, err := schedule.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "weekdays at 07:00 skips the weekend",
+			expr: "0 7 * * 1-5",
+			from: time.Date(2025, 1, 10, 7, 0, 0, 0, berlin), // Friday
+			want: time.Date(2025, 1, 13, 7, 0, 0, 0, berlin), // Monday
+		},
+		{
+			name: "twice daily digest",
+			expr: "0 8,18 * * *",
+			from: time.Date(2025, 1, 10, 9, 30, 0, 0, berlin),
+			want: time.Date(2025, 1, 10, 18, 0, 0, 0, berlin),
+		},
+		{
+			name: "step values",
+			expr: "*/15 * * * *",
+			from: time.Date(2025, 1, 10, 9, 31, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 10, 9, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "day names and descriptors",
+			expr: "@weekly",
+			from: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or weekday when both are restricted",
+			expr: "0 9 1 * 1",
+			from: time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC), // Friday
+			want: time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC), // Monday
+		},
+		{
+			name: "stepped day of month still needs the weekday",
+			expr: "0 9 */2 * 1",
+			from: time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC), // Friday
+			want: time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC), // odd day and a Monday, not Saturday the 11th
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := schedule.ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cron.Next(tt.from))
+		})
+	}
+
+	_, err = schedule.ParseCron("0 25 * * *")
+	assert.Error(t, err)
+}
+
+func TestRules_IsDue(t *testing.T) {
+	tokyo, err := schedule.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 07:00 weekdays in Tokyo is 22:00 UTC the day before
+	rules := schedule.Rules{Cron: "0 7 * * 1-5", Location: tokyo}
+	lastSent := time.Date(2025, 1, 8, 22, 0, 0, 0, time.UTC) // Thu 07:00 JST
+
+	due, err := rules.IsDue(time.Date(2025, 1, 9, 21, 59, 0, 0, time.UTC), &lastSent, created)
+	require.NoError(t, err)
+	assert.False(t, due)
+
+	due, err = rules.IsDue(time.Date(2025, 1, 9, 22, 0, 0, 0, time.UTC), &lastSent, created) // Fri 07:00 JST
+	require.NoError(t, err)
+	assert.True(t, due)
+
+	// Quiet hours hold an interval subscription until the window ends
+	quiet := schedule.Rules{Interval: time.Hour, QuietHours: "23:00-07:00", Location: tokyo}
+	lastSent = time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+
+	due, err = quiet.IsDue(time.Date(2025, 1, 9, 15, 0, 0, 0, time.UTC), &lastSent, created) // 00:00 JST
+	require.NoError(t, err)
+	assert.False(t, due)
+
+	due, err = quiet.IsDue(time.Date(2025, 1, 9, 22, 0, 0, 0, time.UTC), &lastSent, created) // 07:00 JST
+	require.NoError(t, err)
+	assert.True(t, due)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-messaging/entity"
+	"go-messaging/model"
+	"go-messaging/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationStore_ExpiresAndIsPerChat(t *testing.T) {
+	store := model.NewConversationStore(50 * time.Millisecond)
+	store.Start(100, 1, model.Conversation{Flow: "settings", NotificationType: "price_alert", Field: "threshold"})
+
+	conversation := store.Get(100, 1)
+	require.NotNil(t, conversation)
+	assert.Equal(t, "threshold", conversation.Field)
+
+	// The same user in another chat has no conversation
+	assert.Nil(t, store.Get(-200, 1))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Nil(t, store.Get(100, 1))
+}
+
+// testNotificationType builds a notification type whose preference schema is given as JSON
+func testNotificationType(t *testing.T, code, schema string) *entity.NotificationType {
+	t.Helper()
+	notificationType := &entity.NotificationType{Code: code, Name: code, IsActive: true}
+	require.NoError(t, json.Unmarshal([]byte(schema), &notificationType.PreferenceSchema))
+	return notificationType
+}
+
+const priceAlertSchema = `{
+	"properties": {
+		"currency": {"type": "string", "format": "currency", "default": "BTC"},
+		"quote": {"type": "string"},
+		"threshold": {"type": "number", "format": "price", "minimum": 0.01, "default": 50000},
+		"direction": {"type": "string", "enum": ["above", "below", "both"], "default": "above"}
+	},
+	"required": ["currency", "threshold"]
+}`
+
+func TestPreferenceFields_ValidateAndSet(t *testing.T) {
+	priceAlert := testNotificationType(t, "price_alert", priceAlertSchema)
+	news := testNotificationType(t, "news", `{"properties": {"keywords": {"type": "array"}}}`)
+	weather := testNotificationType(t, "weather", `{"properties": {"location": {"type": "string"}}}`)
+
+	prefs := entity.SubscriptionPreferences{}
+
+	threshold, ok := service.FindPreferenceField(priceAlert, "threshold")
+	require.True(t, ok)
+	assert.Error(t, threshold.Set(&prefs, "lots"))
+	assert.Error(t, threshold.Set(&prefs, "-5"))
+	require.NoError(t, threshold.Set(&prefs, "$3500"))
+	assert.Equal(t, 3500.0, prefs.Threshold)
+	assert.Equal(t, "$3500.00", threshold.Get(&prefs))
+
+	currency, _ := service.FindPreferenceField(priceAlert, "currency")
+	require.NoError(t, currency.Set(&prefs, "eth"))
+	assert.Equal(t, "ETH", prefs.Currency)
+
+	direction, _ := service.FindPreferenceField(priceAlert, "direction")
+	assert.Equal(t, []string{"above", "below", "both"}, direction.Options)
+	require.NoError(t, direction.Set(&prefs, "Below"))
+	assert.Equal(t, "below", prefs.Direction)
+
+	interval, _ := service.FindPreferenceField(priceAlert, "interval")
+	assert.Error(t, interval.Set(&prefs, "0"))
+	assert.Error(t, interval.Set(&prefs, "1.5"))
+	require.NoError(t, interval.Set(&prefs, "15"))
+	assert.Equal(t, 15, prefs.Interval)
+
+	keywords, _ := service.FindPreferenceField(news, "keywords")
+	require.NoError(t, keywords.Set(&prefs, "golang, kubernetes,"))
+	assert.Equal(t, []string{"golang", "kubernetes"}, prefs.Keywords)
+	assert.Equal(t, "golang, kubernetes", keywords.Get(&prefs))
+	require.NoError(t, keywords.Set(&prefs, "off"))
+	assert.Empty(t, prefs.Keywords)
+
+	location, _ := service.FindPreferenceField(weather, "location")
+	require.NoError(t, location.Set(&prefs, "Berlin"))
+	assert.Equal(t, "Berlin", prefs.Settings["location"])
+
+	// Fields belong to their own notification type
+	_, ok = service.FindPreferenceField(news, "threshold")
+	assert.False(t, ok)
+}
+
+func TestParsePreferenceArgs(t *testing.T) {
+	priceAlert := testNotificationType(t, "price_alert", priceAlertSchema)
+
+	prefs := entity.SubscriptionPreferences{Currency: "BTC", Threshold: 50000, Direction: "above"}
+	err := service.ParsePreferenceArgs(priceAlert, []string{"currency=ETH", "threshold=3500", "interval=10"}, &prefs)
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", prefs.Currency)
+	assert.Equal(t, 3500.0, prefs.Threshold)
+	assert.Equal(t, 10, prefs.Interval)
+	assert.Equal(t, "above", prefs.Direction)
+
+	news := entity.SubscriptionPreferences{}
+	newsType := testNotificationType(t, "news", `{"properties": {"keywords": {"type": "array"}}}`)
+	require.NoError(t, service.ParsePreferenceArgs(newsType, []string{"keywords=golang,kubernetes"}, &news))
+	assert.Equal(t, []string{"golang", "kubernetes"}, news.Keywords)
+
+	// Words without '=' continue the previous value
+	weather := entity.SubscriptionPreferences{}
+	weatherType := testNotificationType(t, "weather", `{"properties": {"location": {"type": "string"}}}`)
+	require.NoError(t, service.ParsePreferenceArgs(weatherType, []string{"location=New", "York"}, &weather))
+	assert.Equal(t, "New York", weather.Settings["location"])
+
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"colour=red"}, `unknown setting "colour" for price_alert`},
+		{[]string{"threshold=abc"}, `threshold must be a number, got "abc"`},
+		{[]string{"currency=B!TC"}, `invalid currency "B!TC"`},
+		{[]string{"schedule=every", "day"}, `invalid schedule`},
+		{[]string{"3500"}, `expected key=value, got "3500"`},
+	}
+	for _, tt := range tests {
+		err := service.ParsePreferenceArgs(priceAlert, tt.args, &entity.SubscriptionPreferences{})
+		require.Error(t, err, tt.args)
+		assert.Contains(t, err.Error(), tt.message)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpDelivery "go-messaging/delivery/http"
+	"go-messaging/delivery/http/dto"
+	"go-messaging/entity"
+	"go-messaging/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySubscriptionService keeps subscriptions in memory for the HTTP handler
+type memorySubscriptionService struct {
+	service.SubscriptionService
+	subscriptions map[int64]*entity.Subscription
+	filter        service.SubscriptionFilter
+}
+
+func (m *memorySubscriptionService) ListSubscriptions(ctx context.Context, filter service.SubscriptionFilter, offset, limit int) ([]*entity.Subscription, int64, error) {
+	m.filter = filter
+	var matching []*entity.Subscription
+	for _, subscription := range m.subscriptions {
+		if filter.IsActive == nil || subscription.IsActive == *filter.IsActive {
+			matching = append(matching, subscription)
+		}
+	}
+	return matching, int64(len(matching)), nil
+}
+
+func (m *memorySubscriptionService) Subscribe(ctx context.Context, telegramUserID int64, chatID int64, notificationTypeCode string, preferences *entity.SubscriptionPreferences) (*entity.Subscription, error) {
+	if telegramUserID == 2 {
+		return nil, &service.UserNotApprovedError{Status: "pending"}
+	}
+	subscription := &entity.Subscription{
+		ID:               int64(len(m.subscriptions) + 1),
+		ChatID:           chatID,
+		IsActive:         true,
+		NotificationType: entity.NotificationType{Code: notificationTypeCode},
+	}
+	m.subscriptions[subscription.ID] = subscription
+	return subscription, nil
+}
+
+func (m *memorySubscriptionService) GetSubscription(ctx context.Context, id int64) (*entity.Subscription, error) {
+	if subscription, ok := m.subscriptions[id]; ok {
+		return subscription, nil
+	}
+	return nil, service.ErrSubscriptionNotFound
+}
+
+func (m *memorySubscriptionService) SetSubscriptionActive(ctx context.Context, id int64, active bool) (*entity.Subscription, error) {
+	subscription, err := m.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.IsActive = active
+	return subscription, nil
+}
+
+func newSubscriptionRouter(subscriptions service.SubscriptionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes := &httpDelivery.RouteConfig{
+		Router:              router,
+		SubscriptionHandler: httpDelivery.NewSubscriptionHandler(subscriptions),
+	}
+	routes.Setup()
+	return router
+}
+
+// subscriptionRequest builds a request to the subscriptions API with the development credentials
+func subscriptionRequest(method, target string, body []byte) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBuffer(body))
+	req.SetBasicAuth("admin", "admin123")
+	return req
+}
+
+func TestSubscriptionHandler_CreateDefaultsToPrivateChat(t *testing.T) {
+	subscriptions := &memorySubscriptionService{subscriptions: make(map[int64]*entity.Subscription)}
+	router := newSubscriptionRouter(subscriptions)
+
+	body, _ := json.Marshal(dto.CreateSubscriptionRequest{TelegramUserID: 42, NotificationType: "coinbase"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, subscriptionRequest(http.MethodPost, "/api/v1/subscriptions", body))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response dto.SubscriptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(42), response.ChatID)
+	assert.Equal(t, "coinbase", response.NotificationType)
+
+	// Unapproved users cannot subscribe
+	body, _ = json.Marshal(dto.CreateSubscriptionRequest{TelegramUserID: 2, NotificationType: "coinbase"})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, subscriptionRequest(http.MethodPost, "/api/v1/subscriptions", body))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSubscriptionHandler_PauseAndList(t *testing.T) {
+	subscriptions := &memorySubscriptionService{subscriptions: map[int64]*entity.Subscription{
+		1: {ID: 1, ChatID: 42, IsActive: true},
+		2: {ID: 2, ChatID: 43, IsActive: true},
+	}}
+	router := newSubscriptionRouter(subscriptions)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, subscriptionRequest(http.MethodPost, "/api/v1/subscriptions/1/pause", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, subscriptions.subscriptions[1].IsActive)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, subscriptionRequest(http.MethodGet, "/api/v1/subscriptions?active=true&chat_id=43&type=coinbase", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var page dto.PaginatedSubscriptionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Subscriptions, 1)
+	assert.Equal(t, int64(2), page.Subscriptions[0].ID)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, int64(43), *subscriptions.filter.ChatID)
+	assert.Equal(t, "coinbase", subscriptions.filter.NotificationTypeCode)
+
+	// Unknown subscriptions are reported as such
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, subscriptionRequest(http.MethodPost, "/api/v1/subscriptions/99/resume", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSubscriptionHandler_RequiresCredentials(t *testing.T) {
+	subscriptions := &memorySubscriptionService{subscriptions: map[int64]*entity.Subscription{
+		1: {ID: 1, ChatID: 42, IsActive: true},
+	}}
+	router := newSubscriptionRouter(subscriptions)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/subscriptions"},
+		{http.MethodPost, "/api/v1/subscriptions"},
+		{http.MethodGet, "/api/v1/subscriptions/1"},
+		{http.MethodPost, "/api/v1/subscriptions/1/pause"},
+		{http.MethodDelete, "/api/v1/subscriptions/1"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(route.method, route.path, nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s", route.method, route.path)
+	}
+	assert.True(t, subscriptions.subscriptions[1].IsActive)
+}
+
+func TestSubscriptionHandler_RejectsChatTheUserCannotManage(t *testing.T) {
+	subscriptionService, stored := newGroupFixture()
+	subscriptionService.SetChatAdminChecker(chatAdmins{1: true})
+	router := newSubscriptionRouter(subscriptionService)
+
+	// A plain member cannot subscribe the group, nor another user's private chat
+	for _, chatID := range []int64{testGroupID, 1} {
+		body, _ := json.Marshal(dto.CreateSubscriptionRequest{TelegramUserID: 2, ChatID: &chatID, NotificationType: "coinbase"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, subscriptionRequest(http.MethodPost, "/api/v1/subscriptions", body))
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	}
+	assert.Empty(t, stored.subscriptions)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"go-messaging/database"
+	"go-messaging/entity"
+	"go-messaging/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDatabase connects to the Postgres database in DATABASE_URL, skipping the
+// test when it is not set
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error)
+	require.NoError(t, (&database.Database{Connection: db}).AutoMigrate())
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// seedDueSubscriptions creates a notification type with count due subscriptions of one
+// approved user, removing them again when the test ends
+func seedDueSubscriptions(t *testing.T, db *gorm.DB, count int) (*entity.NotificationType, []int64) {
+	t.Helper()
+
+	notificationType := &entity.NotificationType{
+		Code:                   fmt.Sprintf("claim_test_%d", time.Now().UnixNano()),
+		Name:                   "Claim Test",
+		DefaultIntervalMinutes: 60,
+		IsActive:               true,
+	}
+	require.NoError(t, db.Create(notificationType).Error)
+
+	user := &entity.User{ID: uuid.New(), TelegramUserID: time.Now().UnixNano(), ApprovalStatus: "approved"}
+	require.NoError(t, db.Create(user).Error)
+
+	ids := make([]int64, count)
+	for i := range ids {
+		subscription := &entity.Subscription{
+			UserID:             user.ID,
+			ChatID:             user.TelegramUserID,
+			NotificationTypeID: notificationType.ID,
+			IsActive:           true,
+		}
+		require.NoError(t, db.Create(subscription).Error)
+		ids[i] = subscription.ID
+	}
+
+	t.Cleanup(func() {
+		db.Where("notification_type_id = ?", notificationType.ID).Delete(&entity.Subscription{})
+		db.Delete(user)
+		db.Delete(notificationType)
+	})
+	return notificationType, ids
+}
+
+func TestSubscriptionRepository_ConcurrentClaimsAreDisjoint(t *testing.T) {
+	db := openTestDatabase(t)
+	notificationType, ids := seedDueSubscriptions(t, db, 50)
+	subscriptions := repository.NewSubscriptionRepository(db)
+
+	var (
+		wg      sync.WaitGroup
+		start   = make(chan struct{})
+		claimed [2][]*entity.Subscription
+		errs    [2]error
+	)
+	for i := range claimed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			claimed[i], errs[i] = subscriptions.ClaimDueForNotification(context.Background(), notificationType.ID)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// Every due subscription is claimed exactly once between the two claimers
+	seen := make(map[int64]int)
+	for i := range claimed {
+		require.NoError(t, errs[i])
+		for _, subscription := range claimed[i] {
+			seen[subscription.ID]++
+		}
+	}
+	assert.Len(t, seen, len(ids))
+	for id, count := range seen {
+		assert.Equal(t, 1, count, "subscription %d claimed more than once", id)
+	}
+
+	// Claimed subscriptions are no longer due
+	again, err := subscriptions.ClaimDueForNotification(context.Background(), notificationType.ID)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
+
+func TestSubscriptionRepository_ClaimSkipsLockedRows(t *testing.T) {
+	db := openTestDatabase(t)
+	notificationType, ids := seedDueSubscriptions(t, db, 4)
+	subscriptions := repository.NewSubscriptionRepository(db)
+
+	// Another claimer holds the first two rows in an open transaction
+	locker := db.Begin()
+	require.NoError(t, locker.Error)
+	defer locker.Rollback()
+	require.NoError(t, locker.Exec("SELECT id FROM subscriptions WHERE id IN ? FOR UPDATE", ids[:2]).Error)
+
+	// The claim returns the unlocked rows instead of waiting for the lock
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	claimed, err := subscriptions.ClaimDueForNotification(ctx, notificationType.ID)
+	require.NoError(t, err)
+
+	claimedIDs := make([]int64, len(claimed))
+	for i, subscription := range claimed {
+		claimedIDs[i] = subscription.ID
+	}
+	assert.ElementsMatch(t, ids[2:], claimedIDs)
+
+	// Once released, the locked rows are claimed by the next tick
+	require.NoError(t, locker.Rollback().Error)
+	claimed, err = subscriptions.ClaimDueForNotification(context.Background(), notificationType.ID)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 2)
+}
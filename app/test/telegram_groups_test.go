@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-messaging/entity"
+	"go-messaging/repository"
+	"go-messaging/service"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// telegramCall is one Bot API request received by fakeTelegramAPI
+type telegramCall struct {
+	method string
+	params map[string]string
+}
+
+// fakeTelegramAPI answers Bot API requests and records them. Users listed in admins
+// are administrators of every chat; everyone else is a plain member.
+type fakeTelegramAPI struct {
+	*httptest.Server
+
+	admins map[int64]bool
+
+	mutex sync.Mutex
+	calls []telegramCall
+}
+
+func newFakeTelegramAPI(admins ...int64) *fakeTelegramAPI {
+	api := &fakeTelegramAPI{admins: make(map[int64]bool)}
+	for _, id := range admins {
+		api.admins[id] = true
+	}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.handle))
+	return api
+}
+
+func (api *fakeTelegramAPI) handle(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	params := make(map[string]string)
+	if err := r.ParseMultipartForm(1 << 20); err == nil {
+		for key, values := range r.MultipartForm.Value {
+			params[key] = values[0]
+		}
+	}
+
+	api.mutex.Lock()
+	api.calls = append(api.calls, telegramCall{method: method, params: params})
+	api.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch method {
+	case "getChatMember":
+		userID, _ := strconv.ParseInt(params["user_id"], 10, 64)
+		status := "member"
+		if api.admins[userID] {
+			status = "administrator"
+		}
+		fmt.Fprintf(w, `{"ok":true,"result":{"status":%q,"user":{"id":%d}}}`, status, userID)
+	case "sendMessage", "editMessageText":
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":%s,"type":"group"}}}`, params["chat_id"])
+	default:
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}
+}
+
+// sent returns the requests that sent or edited a message
+func (api *fakeTelegramAPI) sent() []telegramCall {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	var sent []telegramCall
+	for _, call := range api.calls {
+		if call.method == "sendMessage" || call.method == "editMessageText" {
+			sent = append(sent, call)
+		}
+	}
+	return sent
+}
+
+// chatSubscriptionRepository keeps subscriptions in memory, keyed by chat and type
+type chatSubscriptionRepository struct {
+	repository.SubscriptionRepository
+	subscriptions []*entity.Subscription
+}
+
+func (r *chatSubscriptionRepository) GetByChatAndType(ctx context.Context, chatID int64, notificationTypeID int) (*entity.Subscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.ChatID == chatID && subscription.NotificationTypeID == notificationTypeID {
+			return subscription, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *chatSubscriptionRepository) Create(ctx context.Context, subscription *entity.Subscription) error {
+	subscription.ID = int64(len(r.subscriptions) + 1)
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+func (r *chatSubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) error {
+	return nil
+}
+
+func (r *chatSubscriptionRepository) DeleteByChatAndType(ctx context.Context, chatID int64, notificationTypeID int) error {
+	for i, subscription := range r.subscriptions {
+		if subscription.ChatID == chatID && subscription.NotificationTypeID == notificationTypeID {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// chatAdmins is a ChatAdminChecker backed by a fixed set of group admins
+type chatAdmins map[int64]bool
+
+func (a chatAdmins) IsChatAdmin(ctx context.Context, chatID, userID int64) (bool, error) {
+	return a[userID], nil
+}
+
+const testGroupID = -1001
+
+func newGroupFixture() (service.SubscriptionService, *chatSubscriptionRepository) {
+	users := &memoryUserRepository{users: map[int64]*entity.User{
+		1: {ID: uuid.New(), TelegramUserID: 1, ApprovalStatus: "approved", Role: "user"},  // group admin
+		2: {ID: uuid.New(), TelegramUserID: 2, ApprovalStatus: "approved", Role: "user"},  // group member
+		3: {ID: uuid.New(), TelegramUserID: 3, ApprovalStatus: "approved", Role: "admin"}, // bot admin
+	}}
+	types := &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", Name: "Coinbase Alerts", DefaultIntervalMinutes: 1, IsActive: true},
+	}}
+	subscriptions := &chatSubscriptionRepository{}
+	return service.NewSubscriptionService(subscriptions, users, types, nil, nil), subscriptions
+}
+
+func TestSubscriptionService_OnlyChatAdminsManageGroups(t *testing.T) {
+	subscriptionService, subscriptions := newGroupFixture()
+	subscriptionService.SetChatAdminChecker(chatAdmins{1: true})
+	ctx := context.Background()
+
+	// A member cannot subscribe the group, but can manage their private chat
+	_, err := subscriptionService.Subscribe(ctx, 2, testGroupID, "coinbase", nil)
+	assert.True(t, errors.Is(err, service.ErrChatAccessDenied), "%v", err)
+	_, err = subscriptionService.Subscribe(ctx, 2, 2, "coinbase", nil)
+	require.NoError(t, err)
+
+	_, err = subscriptionService.Subscribe(ctx, 1, testGroupID, "coinbase", nil)
+	require.NoError(t, err)
+
+	// Nor take over, change or remove the group's subscription
+	_, err = subscriptionService.Subscribe(ctx, 2, testGroupID, "coinbase", nil)
+	assert.True(t, errors.Is(err, service.ErrChatAccessDenied), "%v", err)
+	err = subscriptionService.UpdatePreferences(ctx, 2, testGroupID, "coinbase", &entity.SubscriptionPreferences{Interval: 5})
+	assert.True(t, errors.Is(err, service.ErrChatAccessDenied), "%v", err)
+	err = subscriptionService.Unsubscribe(ctx, 2, testGroupID, "coinbase")
+	assert.True(t, errors.Is(err, service.ErrChatAccessDenied), "%v", err)
+
+	group, _ := subscriptions.GetByChatAndType(ctx, testGroupID, 1)
+	require.NotNil(t, group)
+	assert.Equal(t, 1, group.Preferences.Interval)
+
+	// Bot admins manage any chat
+	require.NoError(t, subscriptionService.UpdatePreferences(ctx, 3, testGroupID, "coinbase", &entity.SubscriptionPreferences{Interval: 5}))
+	require.NoError(t, subscriptionService.Unsubscribe(ctx, 3, testGroupID, "coinbase"))
+	assert.Len(t, subscriptions.subscriptions, 1)
+}
+
+func TestSubscriptionService_OwnerManagesGroupWithoutChecker(t *testing.T) {
+	subscriptionService, subscriptions := newGroupFixture()
+	ctx := context.Background()
+
+	// Without a checker only bot admins may subscribe a group
+	_, err := subscriptionService.Subscribe(ctx, 1, testGroupID, "coinbase", nil)
+	assert.True(t, errors.Is(err, service.ErrChatAccessDenied), "%v", err)
+
+	_, err = subscriptionService.Subscribe(ctx, 3, testGroupID, "coinbase", nil)
+	require.NoError(t, err)
+
+	// The subscription's owner keeps managing it
+	require.NoError(t, subscriptionService.Unsubscribe(ctx, 3, testGroupID, "coinbase"))
+	assert.Empty(t, subscriptions.subscriptions)
+}
+
+func newGroupBot(t *testing.T, api *fakeTelegramAPI) (*service.TelegramBotService, *chatSubscriptionRepository) {
+	t.Helper()
+
+	subscriptionService, subscriptions := newGroupFixture()
+	types := &memoryTypeRepository{types: []*entity.NotificationType{
+		{ID: 1, Code: "coinbase", Name: "Coinbase Alerts", DefaultIntervalMinutes: 1, IsActive: true},
+	}}
+
+	telegramBot := service.NewTelegramBotService("test-token", nil, subscriptionService,
+		service.NewNotificationTypeService(types, nil), nil, service.NewContentProviderRegistry(), 0,
+		bot.WithServerURL(api.URL), bot.WithSkipGetMe())
+	subscriptionService.SetChatAdminChecker(telegramBot)
+
+	return telegramBot, subscriptions
+}
+
+// groupCallback is a button press by userID on message 55 in the test group
+func groupCallback(userID int64, data string) *models.Update {
+	return &models.Update{CallbackQuery: &models.CallbackQuery{
+		ID:   "callback",
+		From: models.User{ID: userID},
+		Message: models.MaybeInaccessibleMessage{
+			Type:    models.MaybeInaccessibleMessageTypeMessage,
+			Message: &models.Message{ID: 55, Chat: models.Chat{ID: testGroupID, Type: "group"}},
+		},
+		Data: data,
+	}}
+}
+
+func TestGroupCallback_RepliesInGroup(t *testing.T) {
+	api := newFakeTelegramAPI(1)
+	defer api.Close()
+	telegramBot, subscriptions := newGroupBot(t, api)
+
+	telegramBot.HandleUpdate(context.Background(), nil, groupCallback(1, "subscribe:coinbase"))
+
+	// The group is subscribed and the button's message in the group is edited
+	require.Len(t, subscriptions.subscriptions, 1)
+	assert.Equal(t, int64(testGroupID), subscriptions.subscriptions[0].ChatID)
+
+	sent := api.sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "editMessageText", sent[0].method)
+	assert.Equal(t, strconv.Itoa(testGroupID), sent[0].params["chat_id"])
+	assert.Equal(t, "55", sent[0].params["message_id"])
+	assert.Contains(t, sent[0].params["text"], "Successfully subscribed to Coinbase Alerts")
+}
+
+func TestGroupCallback_RefusesNonAdmin(t *testing.T) {
+	api := newFakeTelegramAPI(1)
+	defer api.Close()
+	telegramBot, subscriptions := newGroupBot(t, api)
+
+	telegramBot.HandleUpdate(context.Background(), nil, groupCallback(2, "subscribe:coinbase"))
+
+	assert.Empty(t, subscriptions.subscriptions)
+
+	sent := api.sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, strconv.Itoa(testGroupID), sent[0].params["chat_id"])
+	assert.Contains(t, sent[0].params["text"], "Only group admins can change this group's subscriptions")
+}
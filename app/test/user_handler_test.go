@@ -11,6 +11,7 @@ import (
 	httpDelivery "go-messaging/delivery/http"
 	"go-messaging/delivery/http/dto"
 	"go-messaging/entity"
+	"go-messaging/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -58,6 +59,15 @@ func (m *MockUserService) CountUsers(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserService) UpdateTimezone(ctx context.Context, telegramUserID int64, timezone string) error {
+	args := m.Called(ctx, telegramUserID, timezone)
+	return args.Error(0)
+}
+
+func (m *MockUserService) SetRegistrationNotifier(notifier service.RegistrationNotifier) {
+	m.Called(notifier)
+}
+
 func TestUserHandler_CreateUser(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -0,0 +1,13 @@
+-- Migration: Add approval notifications
+-- Records the message each admin was sent about a pending user, so admins are
+-- told once per user and the messages can be updated after someone acts
+
+CREATE TABLE IF NOT EXISTS approval_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    admin_chat_id BIGINT NOT NULL,
+    message_id INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_notifications_user_chat ON approval_notifications(user_id, admin_chat_id);
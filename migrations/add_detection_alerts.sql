@@ -0,0 +1,15 @@
+-- Migration: Deliver scanner detections to the security notification type
+-- POST /api/v1/detections sends each detection to the security subscriptions
+-- whose min_risk it meets. Without min_risk only high and critical detections
+-- are delivered, so on-call chats are not paged for routine findings. The type
+-- has no content provider, so the scheduler does not send it on its own.
+
+INSERT INTO notification_types (code, name, description, default_interval_minutes) VALUES
+('security', 'Security Alerts', 'Security-related notifications', 1)
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "min_risk": {"type": "string", "title": "🚨 Minimum risk", "description": "Which detections should I send you? Pick the lowest risk level.", "enum": ["low", "medium", "high", "critical"], "default": "high"}
+  }
+}' WHERE code = 'security' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
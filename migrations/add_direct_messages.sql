@@ -0,0 +1,13 @@
+-- Migration: Direct messages through the outbox
+-- POST /api/v1/messages queues messages for a chat outside any subscription.
+-- They carry a parse mode and inline buttons, record the Telegram message ID
+-- once sent, and are logged in notification_logs against their chat.
+
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS parse_mode VARCHAR(20);
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS buttons JSONB;
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS telegram_message_id BIGINT;
+
+ALTER TABLE notification_logs ALTER COLUMN subscription_id DROP NOT NULL;
+ALTER TABLE notification_logs ADD COLUMN IF NOT EXISTS chat_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_notification_logs_chat_id ON notification_logs(chat_id);
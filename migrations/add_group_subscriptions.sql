@@ -0,0 +1,16 @@
+-- Migration: Key subscriptions by chat instead of by user
+-- Groups can now be subscribed by their admins. A chat subscribes to a type at
+-- most once and user_id records who subscribed it, so one user can own both a
+-- private subscription and subscriptions for the groups they manage.
+
+-- Keep the oldest subscription where the same chat was subscribed more than once
+DELETE FROM subscriptions s
+USING subscriptions older
+WHERE s.chat_id = older.chat_id
+  AND s.notification_type_id = older.notification_type_id
+  AND s.id > older.id;
+
+ALTER TABLE subscriptions
+DROP CONSTRAINT IF EXISTS subscriptions_user_id_notification_type_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_chat_type ON subscriptions(chat_id, notification_type_id);
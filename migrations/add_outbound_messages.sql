@@ -0,0 +1,20 @@
+-- Migration: Add durable outbound message queue
+-- Dispatch writes messages here and the outbox sender delivers them with
+-- retries; messages that exhaust their retries are kept with status 'dead'
+
+CREATE TABLE IF NOT EXISTS outbound_messages (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT REFERENCES subscriptions(id) ON DELETE SET NULL,
+    chat_id BIGINT NOT NULL,
+    message TEXT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending', -- pending, sending, sent, dead
+    attempts INTEGER DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    locked_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_due ON outbound_messages(status, next_attempt_at);
@@ -0,0 +1,44 @@
+-- Migration: Add per-type preference schemas
+-- Each notification type declares which subscription preferences it accepts,
+-- which are required, and their defaults and bounds. interval, schedule and
+-- quiet_hours are accepted for every type and need not be declared.
+
+ALTER TABLE notification_types
+ADD COLUMN IF NOT EXISTS preference_schema JSONB DEFAULT '{}';
+
+-- Seed schemas for the built-in types, leaving any edited schema alone
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "currency": {"type": "string", "format": "currency", "title": "💱 Currency", "description": "Which currency? Send a ticker such as BTC or ETH.", "default": "BTC"},
+    "quote": {"type": "string", "title": "💵 Quote currency", "description": "Which currency should prices be quoted in? Send a code such as USD or EUR. Send ''off'' for USD."}
+  }
+}' WHERE code = 'coinbase' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "currency": {"type": "string", "format": "currency", "title": "💱 Currency", "description": "Which currency? Send a ticker such as BTC or ETH.", "default": "BTC"},
+    "quote": {"type": "string", "title": "💵 Quote currency", "description": "Which currency should prices be quoted in? Send a code such as USD or EUR. Send ''off'' for USD."},
+    "threshold": {"type": "number", "format": "price", "title": "🎯 Threshold", "description": "At what price should I alert you? Send a number such as 3500.", "minimum": 0.01, "default": 50000},
+    "direction": {"type": "string", "title": "↕️ Direction", "description": "Alert when the price crosses the threshold going which way?", "enum": ["above", "below", "both"], "default": "above"},
+    "rearm_percent": {"type": "number", "title": "🔁 Re-arm margin", "description": "How far, in percent, must the price move back before the alert can fire again? Send ''off'' for the default.", "minimum": 0, "maximum": 50}
+  },
+  "required": ["currency", "threshold"]
+}' WHERE code = 'price_alert' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "keywords": {"type": "array", "title": "🔎 Keywords", "description": "Which topics? Send keywords separated by commas, e.g. golang,kubernetes. Send ''off'' to clear."}
+  }
+}' WHERE code = 'news' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "location": {"type": "string", "title": "📍 Location", "description": "Which location? Send a city name, e.g. Berlin."}
+  }
+}' WHERE code = 'weather' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
+
+UPDATE notification_types SET preference_schema = '{
+  "properties": {
+    "message": {"type": "string", "title": "💬 Message", "description": "What should the notification say?"}
+  }
+}' WHERE code = 'custom' AND (preference_schema IS NULL OR preference_schema = '{}'::jsonb);
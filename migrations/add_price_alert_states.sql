@@ -0,0 +1,17 @@
+-- Migration: Add edge-triggered price alert state
+-- Stores the last observed price per price_alert subscription so alerts
+-- fire once per threshold crossing instead of on every run
+
+CREATE TABLE IF NOT EXISTS price_alert_states (
+    subscription_id BIGINT PRIMARY KEY REFERENCES subscriptions(id) ON DELETE CASCADE,
+    last_price DOUBLE PRECISION,
+    state VARCHAR(10) NOT NULL, -- above, below
+    threshold DOUBLE PRECISION,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Existing price alerts default to alerting on upward crossings
+UPDATE subscriptions
+SET preferences = preferences || '{"direction": "above"}'::jsonb
+WHERE notification_type_id = (SELECT id FROM notification_types WHERE code = 'price_alert')
+AND NOT preferences ? 'direction';
@@ -0,0 +1,6 @@
+-- Migration: Add per-user time zone
+-- Cron schedules ("schedule") and quiet hours ("quiet_hours") in subscription
+-- preferences are evaluated in this time zone
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) DEFAULT 'UTC';